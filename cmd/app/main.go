@@ -1,18 +1,29 @@
 package main
 
 import (
+	"app/internal/blob"
 	"app/internal/config"
 	"app/internal/database"
 	_ "app/internal/docs"
+	"app/internal/entity"
 	"app/internal/handler"
+	"app/internal/mail"
+	"app/internal/notify"
 	"app/internal/repository"
+	"app/internal/scheduler"
 	"app/internal/usecase"
+	"context"
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // @title           Task Manager API
 // @version         1.0
 // @description     CRUD задач с регистрацией, логином (JWT) и защищёнными эндпоинтами.
+// @description     Рабочее пространство запроса задаётся заголовком X-Workspace-ID (по умолчанию — личное).
 // @BasePath        /
 // @schemes         http
 // @securityDefinitions.apikey BearerAuth
@@ -32,12 +43,158 @@ func main() {
 
 	UserDB := repository.NewUserRepo(DB)
 	TaskDB := repository.NewTaskRepo(DB)
+	WorkflowDB := repository.NewWorkflowRepo(DB)
+	LabelDB := repository.NewLabelRepo(DB)
+	ProjectDB := repository.NewProjectRepo(DB)
+	ReminderDB := repository.NewReminderRepo(DB)
+	CommentDB := repository.NewCommentRepo(DB)
+	AttachmentDB := repository.NewAttachmentRepo(DB)
+	ChecklistDB := repository.NewChecklistRepo(DB)
+	ShareDB := repository.NewShareRepo(DB)
+	WorkspaceDB := repository.NewWorkspaceRepo(DB)
+	RBACDB := repository.NewRBACRepo(DB)
+	SessionDB := repository.NewSessionRepo(DB)
+	RevocationDB := repository.NewRevocationRepo(DB)
 
-	UserUC := usecase.NewUserUseCase(UserDB)
-	TaskUC := usecase.NewTaskUseCase(TaskDB)
+	subtaskPolicies, err := loadSubtaskPolicies()
+	if err != nil {
+		log.Fatal(err)
+	}
+	blobs, err := loadBlobStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+	attachmentLimits, err := loadAttachmentLimits()
+	if err != nil {
+		log.Fatal(err)
+	}
+	tokenTTL, err := loadTokenTTL()
+	if err != nil {
+		log.Fatal(err)
+	}
+	mailer, err := newMailer()
+	if err != nil {
+		log.Fatal(err)
+	}
+	mailLinks := entity.MailLinks{
+		PasswordReset: config.C.PasswordResetURL,
+		VerifyEmail:   config.C.VerifyEmailURL,
+	}
+	requireVerifiedEmail, err := strconv.ParseBool(config.C.RequireVerifiedEmail)
+	if err != nil {
+		log.Fatalf("REQUIRE_VERIFIED_EMAIL: invalid bool %q", config.C.RequireVerifiedEmail)
+	}
+
+	Access := usecase.NewAuthorizer(ShareDB, TaskDB, ProjectDB)
+	UserUC := usecase.NewUserUseCase(UserDB, RBACDB, SessionDB, RevocationDB, mailer, mailLinks, tokenTTL, requireVerifiedEmail)
+	TaskUC := usecase.NewTaskUseCase(TaskDB, WorkflowDB, Access, subtaskPolicies)
+	WorkflowUC := usecase.NewWorkflowUseCase(WorkflowDB)
+	LabelUC := usecase.NewLabelUseCase(LabelDB, TaskDB, Access)
+	ProjectUC := usecase.NewProjectUseCase(ProjectDB, Access)
+	ReminderUC := usecase.NewReminderUseCase(ReminderDB, Access, WorkflowDB, newNotifier())
+	CommentUC := usecase.NewCommentUseCase(CommentDB, Access)
+	AttachmentUC := usecase.NewAttachmentUseCase(AttachmentDB, Access, blobs, attachmentLimits)
+	ChecklistUC := usecase.NewChecklistUseCase(ChecklistDB, Access)
+	ShareUC := usecase.NewShareUseCase(ShareDB, UserDB, WorkspaceDB, Access)
+	WorkspaceUC := usecase.NewWorkspaceUseCase(WorkspaceDB, UserDB)
+	RBACUC := usecase.NewRBACUseCase(RBACDB, UserDB)
+	AdminUC := usecase.NewAdminUseCase(UserDB, WorkflowDB)
+
+	if err := RBACUC.Bootstrap(context.Background(), config.C.AdminEmail); err != nil {
+		log.Fatal(err)
+	}
+	if err := UserUC.SyncRevocations(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+
+	interval, err := time.ParseDuration(config.C.SchedulerInterval)
+	if err != nil || interval <= 0 {
+		log.Fatalf("SCHEDULER_INTERVAL: invalid duration %q", config.C.SchedulerInterval)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go scheduler.New(
+		scheduler.Job{Name: "reminders", Interval: interval, Run: ReminderUC.FireDue},
+		scheduler.Job{Name: "blob-gc", Interval: interval, Run: AttachmentUC.CollectGarbage},
+		scheduler.Job{Name: "token-revocations", Interval: interval, Run: UserUC.SyncRevocations},
+		scheduler.Job{Name: "token-gc", Interval: interval, Run: UserUC.PurgeExpiredTokens},
+		scheduler.Job{Name: "session-last-seen", Interval: interval, Run: UserUC.FlushLastSeen},
+	).Run(ctx)
 
-	router, _ := handler.NewHandler(TaskUC, UserUC)
+	router, _ := handler.NewHandler(TaskUC, UserUC, WorkflowUC, LabelUC, ProjectUC, ReminderUC, CommentUC, AttachmentUC, ChecklistUC, ShareUC, WorkspaceUC, RBACUC, AdminUC)
 	if err = router.Run(":3000"); err != nil {
 		log.Fatal(err)
 	}
 }
+
+func loadSubtaskPolicies() (entity.SubtaskPolicies, error) {
+	onComplete, err := entity.ParseSubtaskPolicy(config.C.SubtaskOnComplete)
+	if err != nil {
+		return entity.SubtaskPolicies{}, fmt.Errorf("SUBTASK_ON_COMPLETE: %w", err)
+	}
+	onDelete, err := entity.ParseSubtaskPolicy(config.C.SubtaskOnDelete)
+	if err != nil {
+		return entity.SubtaskPolicies{}, fmt.Errorf("SUBTASK_ON_DELETE: %w", err)
+	}
+	return entity.SubtaskPolicies{OnComplete: onComplete, OnDelete: onDelete}, nil
+}
+
+func newNotifier() usecase.Notifier {
+	if config.C.ReminderWebhookURL != "" {
+		return notify.NewWebhookNotifier(config.C.ReminderWebhookURL)
+	}
+	return notify.LogNotifier{}
+}
+
+func newMailer() (usecase.Mailer, error) {
+	if config.C.SMTPHost == "" {
+		return mail.LogMailer{}, nil
+	}
+	port, err := strconv.Atoi(config.C.SMTPPort)
+	if err != nil || port <= 0 {
+		return nil, fmt.Errorf("SMTP_PORT: invalid port %q", config.C.SMTPPort)
+	}
+	m, err := mail.NewSMTPMailer(config.C.SMTPHost, port, config.C.SMTPUsername, config.C.SMTPPassword, config.C.MailFrom)
+	if err != nil {
+		return nil, fmt.Errorf("MAIL_FROM: %w", err)
+	}
+	return m, nil
+}
+
+func loadBlobStore() (usecase.BlobStore, error) {
+	switch config.C.BlobStore {
+	case "local":
+		return blob.NewLocalStore(config.C.BlobDir)
+	case "s3":
+		return blob.NewS3Store(config.C.S3Endpoint, config.C.S3Region, config.C.S3Bucket,
+			config.C.S3AccessKey, config.C.S3SecretKey)
+	default:
+		return nil, fmt.Errorf("BLOB_STORE: unknown store %q", config.C.BlobStore)
+	}
+}
+
+func loadAttachmentLimits() (entity.AttachmentLimits, error) {
+	maxSize, err := strconv.ParseInt(config.C.AttachmentMaxBytes, 10, 64)
+	if err != nil || maxSize <= 0 {
+		return entity.AttachmentLimits{}, fmt.Errorf("ATTACHMENT_MAX_BYTES: invalid size %q", config.C.AttachmentMaxBytes)
+	}
+	var types []string
+	for _, t := range strings.Split(config.C.AttachmentTypes, ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			types = append(types, t)
+		}
+	}
+	return entity.AttachmentLimits{MaxSize: maxSize, AllowedTypes: types}, nil
+}
+
+func loadTokenTTL() (entity.TokenTTL, error) {
+	access, err := time.ParseDuration(config.C.AccessTokenTTL)
+	if err != nil || access <= 0 {
+		return entity.TokenTTL{}, fmt.Errorf("ACCESS_TOKEN_TTL: invalid duration %q", config.C.AccessTokenTTL)
+	}
+	refresh, err := time.ParseDuration(config.C.RefreshTokenTTL)
+	if err != nil || refresh <= 0 {
+		return entity.TokenTTL{}, fmt.Errorf("REFRESH_TOKEN_TTL: invalid duration %q", config.C.RefreshTokenTTL)
+	}
+	return entity.TokenTTL{Access: access, Refresh: refresh}, nil
+}
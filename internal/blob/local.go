@@ -0,0 +1,76 @@
+// Package blob содержит реализации хранилища файлов вложений. Отсутствующий
+// объект во всех реализациях — ошибка, для которой errors.Is(err, fs.ErrNotExist).
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore хранит файлы в каталоге на диске.
+type LocalStore struct {
+	root string
+}
+
+func NewLocalStore(root string) (*LocalStore, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, err
+	}
+	return &LocalStore{root: root}, nil
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	if key == "" || strings.Contains(key, "..") || filepath.IsAbs(key) {
+		return "", fmt.Errorf("blob: invalid key %q", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// Put пишет во временный файл и переименовывает его, чтобы читатели никогда
+// не увидели недописанный файл.
+func (s *LocalStore) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *LocalStore) Open(_ context.Context, key string) (io.ReadSeekCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStoreRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const key = "tasks/1/report.txt"
+	if err := s.Put(ctx, key, strings.NewReader("hello, world"), 12, "text/plain"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	f, err := s.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if _, err := f.Seek(7, io.SeekStart); err != nil {
+		t.Fatalf("Seek: %v", err)
+	}
+	got, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(got) != "world" {
+		t.Fatalf("read %q, want %q", got, "world")
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Open(ctx, key); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Open after Delete: got %v, want fs.ErrNotExist", err)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete of missing key: %v", err)
+	}
+}
+
+func TestLocalStorePutLeavesNoTempFiles(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocalStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(context.Background(), "a/b", strings.NewReader("x"), 1, ""); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(filepath.Join(root, "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "b" {
+		t.Fatalf("directory contents: %v, want only b", entries)
+	}
+}
+
+func TestLocalStoreRejectsPathTraversal(t *testing.T) {
+	ctx := context.Background()
+	parent := t.TempDir()
+	root := filepath.Join(parent, "root")
+	s, err := NewLocalStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(parent, "secret")
+	if err := os.WriteFile(outside, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{
+		"",
+		"..",
+		"../secret",
+		"a/../../secret",
+		"a/..",
+		filepath.Join(parent, "secret"),
+		"/etc/passwd",
+	}
+	for _, key := range keys {
+		t.Run(key, func(t *testing.T) {
+			if err := s.Put(ctx, key, strings.NewReader("pwned"), 5, ""); err == nil {
+				t.Error("Put: expected error")
+			}
+			if f, err := s.Open(ctx, key); err == nil {
+				f.Close()
+				t.Error("Open: expected error")
+			}
+			if err := s.Delete(ctx, key); err == nil {
+				t.Error("Delete: expected error")
+			}
+		})
+	}
+
+	got, err := os.ReadFile(outside)
+	if err != nil {
+		t.Fatalf("file outside root: %v", err)
+	}
+	if string(got) != "secret" {
+		t.Fatalf("file outside root was modified: %q", got)
+	}
+}
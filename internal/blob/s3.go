@@ -0,0 +1,246 @@
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// s3ResponseTimeout — сколько ждать ответа S3 на запрос. Общий
+// http.Client.Timeout не подходит: он оборвал бы отдачу и загрузку больших
+// файлов, а зависший сервер ловится уже на ожидании заголовков ответа.
+const s3ResponseTimeout = 30 * time.Second
+
+// S3Store хранит файлы в S3-совместимом хранилище (AWS S3, MinIO и т.п.).
+// Запросы подписываются AWS Signature V4, адресация path-style:
+// {endpoint}/{bucket}/{key}.
+type S3Store struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+func NewS3Store(endpoint, region, bucket, accessKey, secretKey string) (*S3Store, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("blob: invalid S3 endpoint %q", endpoint)
+	}
+	if bucket == "" {
+		return nil, errors.New("blob: S3 bucket is required")
+	}
+	return &S3Store{
+		endpoint:  u,
+		region:    region,
+		bucket:    bucket,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client: &http.Client{
+			Transport: &http.Transport{
+				Proxy:                 http.ProxyFromEnvironment,
+				DialContext:           (&net.Dialer{Timeout: 10 * time.Second}).DialContext,
+				TLSHandshakeTimeout:   10 * time.Second,
+				ResponseHeaderTimeout: s3ResponseTimeout,
+				IdleConnTimeout:       90 * time.Second,
+				MaxIdleConnsPerHost:   16,
+			},
+		},
+	}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	h := http.Header{}
+	if contentType != "" {
+		h.Set("Content-Type", contentType)
+	}
+	resp, err := s.do(ctx, http.MethodPut, key, r, size, h)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Open возвращает объект, который читает данные ranged GET-запросами:
+// Seek не качает лишнего, поэтому http.ServeContent отдаёт Range без
+// загрузки всего файла.
+func (s *S3Store) Open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	resp, err := s.do(ctx, http.MethodHead, key, nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return &s3Object{ctx: ctx, store: s, key: key, size: resp.ContentLength}, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, 0, nil)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// do выполняет подписанный запрос к объекту key. Ответы не из 2xx
+// превращаются в ошибку; 404 — в fs.ErrNotExist.
+func (s *S3Store) do(ctx context.Context, method, key string, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(s.endpoint.Path, "/") + "/" + s.bucket + "/" + key
+	u.RawPath = strings.TrimSuffix(s.endpoint.EscapedPath(), "/") + "/" + escapePath(s.bucket) + "/" + escapePath(key)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	s.sign(req, "UNSIGNED-PAYLOAD", time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("blob: %s: %w", key, fs.ErrNotExist)
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return nil, fmt.Errorf("blob: S3 %s %s: %s %s", method, key, resp.Status, strings.TrimSpace(string(msg)))
+}
+
+// sign добавляет заголовки AWS Signature V4. Подписываются host и x-amz-*;
+// тело не хэшируется (UNSIGNED-PAYLOAD), чтобы загружать файлы потоком.
+func (s *S3Store) sign(req *http.Request, payloadHash string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host + "\n" +
+			"x-amz-content-sha256:" + payloadHash + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	digest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(data))
+	return m.Sum(nil)
+}
+
+// escapePath кодирует путь по правилам SigV4: всё, кроме A-Z a-z 0-9 - _ . ~ и /.
+func escapePath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// s3Object читает объект с текущей позиции одним GET с Range: bytes=offset-;
+// после Seek запрос открывается заново.
+type s3Object struct {
+	ctx    context.Context
+	store  *S3Store
+	key    string
+	size   int64
+	offset int64
+	body   io.ReadCloser
+}
+
+func (o *s3Object) Read(p []byte) (int, error) {
+	if o.offset >= o.size {
+		return 0, io.EOF
+	}
+	if o.body == nil {
+		h := http.Header{}
+		h.Set("Range", "bytes="+strconv.FormatInt(o.offset, 10)+"-")
+		resp, err := o.store.do(o.ctx, http.MethodGet, o.key, nil, 0, h)
+		if err != nil {
+			return 0, err
+		}
+		o.body = resp.Body
+	}
+	n, err := o.body.Read(p)
+	o.offset += int64(n)
+	return n, err
+}
+
+func (o *s3Object) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = o.offset + offset
+	case io.SeekEnd:
+		abs = o.size + offset
+	default:
+		return 0, errors.New("blob: invalid whence")
+	}
+	if abs < 0 {
+		return 0, errors.New("blob: negative position")
+	}
+	if abs != o.offset && o.body != nil {
+		o.body.Close()
+		o.body = nil
+	}
+	o.offset = abs
+	return abs, nil
+}
+
+func (o *s3Object) Close() error {
+	if o.body == nil {
+		return nil
+	}
+	return o.body.Close()
+}
@@ -0,0 +1,335 @@
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testAccessKey = "AKIDEXAMPLE"
+	testSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testRegion    = "us-east-1"
+	testBucket    = "attachments"
+)
+
+// fakeS3 — минимальный S3-эндпоинт: проверяет подпись SigV4 и хранит
+// объекты в памяти. Поддерживает PUT, HEAD, GET с Range: bytes=N- и DELETE.
+type fakeS3 struct {
+	t         *testing.T
+	secretKey string
+
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+	ranges  []string
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	f := &fakeS3{
+		t:         t,
+		secretKey: testSecretKey,
+		objects:   map[string][]byte{},
+		types:     map[string]string{},
+	}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := verifySigV4(r, testAccessKey, f.secretKey, testRegion); err != nil {
+		http.Error(w, "SignatureDoesNotMatch: "+err.Error(), http.StatusForbidden)
+		return
+	}
+	prefix := "/" + testBucket + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, prefix)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, ok := f.objects[key]
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.ContentLength != int64(len(body)) {
+			http.Error(w, "content length mismatch", http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = body
+		f.types[key] = r.Header.Get("Content-Type")
+	case http.MethodHead:
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	case http.MethodGet:
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		rng := r.Header.Get("Range")
+		f.ranges = append(f.ranges, rng)
+		start := 0
+		if rng != "" {
+			n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+			if err != nil || n > len(data) {
+				http.Error(w, "InvalidRange", http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+			start = n
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", n, len(data)-1, len(data)))
+			w.WriteHeader(http.StatusPartialContent)
+		}
+		w.Write(data[start:])
+	case http.MethodDelete:
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// verifySigV4 независимо пересчитывает подпись по тому, что пришло на сервер:
+// ошибки кодирования пути или набора заголовков на стороне клиента дают
+// несовпадение.
+func verifySigV4(r *http.Request, accessKey, secretKey, region string) error {
+	const algo = "AWS4-HMAC-SHA256 "
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, algo) {
+		return fmt.Errorf("unexpected Authorization %q", auth)
+	}
+	fields := map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(auth, algo), ", ") {
+		k, v, _ := strings.Cut(part, "=")
+		fields[k] = v
+	}
+
+	amzDate := r.Header.Get("x-amz-date")
+	if _, err := time.Parse("20060102T150405Z", amzDate); err != nil {
+		return fmt.Errorf("bad x-amz-date %q", amzDate)
+	}
+	date := amzDate[:8]
+	scope := date + "/" + region + "/s3/aws4_request"
+	if want := accessKey + "/" + scope; fields["Credential"] != want {
+		return fmt.Errorf("credential %q, want %q", fields["Credential"], want)
+	}
+
+	signed := strings.Split(fields["SignedHeaders"], ";")
+	var canonicalHeaders strings.Builder
+	for _, name := range signed {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	payloadHash := r.Header.Get("x-amz-content-sha256")
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		r.URL.RawQuery,
+		canonicalHeaders.String(),
+		fields["SignedHeaders"],
+		payloadHash,
+	}, "\n")
+	digest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+
+	key := []byte("AWS4" + secretKey)
+	for _, s := range []string{date, region, "s3", "aws4_request"} {
+		m := hmac.New(sha256.New, key)
+		m.Write([]byte(s))
+		key = m.Sum(nil)
+	}
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(stringToSign))
+	if want := hex.EncodeToString(m.Sum(nil)); fields["Signature"] != want {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func newTestS3Store(t *testing.T, endpoint string) *S3Store {
+	s, err := NewS3Store(endpoint, testRegion, testBucket, testAccessKey, testSecretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestNewS3StoreValidation(t *testing.T) {
+	if _, err := NewS3Store("not a url", testRegion, testBucket, "", ""); err == nil {
+		t.Error("expected error for endpoint without host")
+	}
+	if _, err := NewS3Store("http://localhost:9000", testRegion, "", "", ""); err == nil {
+		t.Error("expected error for empty bucket")
+	}
+}
+
+func TestS3StoreSignsRequests(t *testing.T) {
+	f, srv := newFakeS3(t)
+	ctx := context.Background()
+
+	// Ключ с пробелом, юникодом и спецсимволами проверяет кодирование пути
+	// в канонический запрос.
+	keys := []string{"plain.txt", "tasks/1/отчёт за май (v2)+final.pdf", "a/b~c_d-e.f"}
+	for _, key := range keys {
+		s := newTestS3Store(t, srv.URL)
+		if err := s.Put(ctx, key, strings.NewReader("data"), 4, "application/pdf"); err != nil {
+			t.Fatalf("Put %q: %v", key, err)
+		}
+		if got := string(f.objects[key]); got != "data" {
+			t.Fatalf("stored %q under %q", got, key)
+		}
+		if got := f.types[key]; got != "application/pdf" {
+			t.Fatalf("Content-Type %q", got)
+		}
+	}
+
+	// С чужим секретом сервер отвечает 403, и ошибка доходит до вызывающего.
+	bad, err := NewS3Store(srv.URL, testRegion, testBucket, testAccessKey, "wrong-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = bad.Put(ctx, "plain.txt", strings.NewReader("x"), 1, "")
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("Put with wrong secret: got %v, want 403 error", err)
+	}
+}
+
+func TestS3StoreEndpointWithPath(t *testing.T) {
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := verifySigV4(r, testAccessKey, testSecretKey, testRegion); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		gotPath = r.URL.EscapedPath()
+	}))
+	defer srv.Close()
+
+	s := newTestS3Store(t, srv.URL+"/s3/")
+	if err := s.Put(context.Background(), "a b", strings.NewReader(""), 0, ""); err != nil {
+		t.Fatal(err)
+	}
+	if want := "/s3/" + testBucket + "/a%20b"; gotPath != want {
+		t.Fatalf("path %q, want %q", gotPath, want)
+	}
+}
+
+func TestS3StoreRangedRead(t *testing.T) {
+	f, srv := newFakeS3(t)
+	ctx := context.Background()
+	s := newTestS3Store(t, srv.URL)
+
+	const content = "0123456789abcdef"
+	if err := s.Put(ctx, "file.bin", strings.NewReader(content), int64(len(content)), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	obj, err := s.Open(ctx, "file.bin")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer obj.Close()
+
+	size, err := obj.Seek(0, io.SeekEnd)
+	if err != nil || size != int64(len(content)) {
+		t.Fatalf("Seek end: %d, %v", size, err)
+	}
+	if len(f.ranges) != 0 {
+		t.Fatalf("Open and Seek issued GET requests: %v", f.ranges)
+	}
+
+	if _, err := obj.Seek(10, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 3)
+	if _, err := io.ReadFull(obj, buf); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "abc" {
+		t.Fatalf("read %q at offset 10", buf)
+	}
+
+	// Seek на текущую позицию не должен переоткрывать поток.
+	if _, err := obj.Seek(0, io.SeekCurrent); err != nil {
+		t.Fatal(err)
+	}
+	rest, err := io.ReadAll(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(rest) != "def" {
+		t.Fatalf("read %q after offset 13", rest)
+	}
+
+	if _, err := obj.Seek(-4, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	tail, err := io.ReadAll(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(tail) != "cdef" {
+		t.Fatalf("read %q at offset 12", tail)
+	}
+
+	want := []string{"bytes=10-", "bytes=12-"}
+	if strings.Join(f.ranges, ",") != strings.Join(want, ",") {
+		t.Fatalf("Range headers %v, want %v", f.ranges, want)
+	}
+
+	if _, err := obj.Seek(-1, io.SeekStart); err == nil {
+		t.Fatal("expected error for negative position")
+	}
+}
+
+func TestS3StoreDelete(t *testing.T) {
+	f, srv := newFakeS3(t)
+	ctx := context.Background()
+	s := newTestS3Store(t, srv.URL)
+
+	if err := s.Put(ctx, "gone.txt", strings.NewReader("x"), 1, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, "gone.txt"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok := f.objects["gone.txt"]; ok {
+		t.Fatal("object still stored after Delete")
+	}
+	if _, err := s.Open(ctx, "gone.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Open after Delete: got %v, want fs.ErrNotExist", err)
+	}
+	// Удаление отсутствующего объекта не ошибка — как у LocalStore.
+	if err := s.Delete(ctx, "gone.txt"); err != nil {
+		t.Fatalf("Delete of missing key: %v", err)
+	}
+}
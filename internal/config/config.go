@@ -9,6 +9,52 @@ type Config struct {
 	DatabaseURL string
 	BaseURL     string
 	Secret      string
+
+	// AdminEmail — кому при старте выдать системную роль admin
+	// (пользователь должен быть уже зарегистрирован).
+	AdminEmail string
+
+	// AccessTokenTTL и RefreshTokenTTL — сроки жизни JWT и refresh-токена
+	// (time.ParseDuration).
+	AccessTokenTTL  string
+	RefreshTokenTTL string
+
+	// SMTP для писем пользователям; без SMTPHost письма пишутся в лог.
+	// MailFrom — отправитель, PasswordResetURL — ссылка из письма о сбросе
+	// пароля, к ней дописывается токен.
+	SMTPHost         string
+	SMTPPort         string
+	SMTPUsername     string
+	SMTPPassword     string
+	MailFrom         string
+	PasswordResetURL string
+	VerifyEmailURL   string
+
+	// RequireVerifiedEmail — true: без подтверждённой почты нельзя создавать задачи.
+	RequireVerifiedEmail string
+
+	// cascade | block | orphan — см. entity.SubtaskPolicy
+	SubtaskOnComplete string
+	SubtaskOnDelete   string
+
+	// SchedulerInterval — как часто планировщик ищет сработавшие напоминания;
+	// ReminderWebhookURL — куда их доставлять (пусто — только в лог).
+	SchedulerInterval  string
+	ReminderWebhookURL string
+
+	// BlobStore — local | s3: где хранить вложения.
+	BlobStore   string
+	BlobDir     string
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+
+	// AttachmentMaxBytes — максимальный размер файла, AttachmentTypes —
+	// разрешённые MIME-типы через запятую.
+	AttachmentMaxBytes string
+	AttachmentTypes    string
 }
 
 var C Config
@@ -21,6 +67,38 @@ func init() {
 		DatabaseURL: getEnv("POSTGRES_URL", ""),
 		BaseURL:     getEnv("BASE_URL", ""),
 		Secret:      getEnv("SECRET_KEY", ""),
+
+		AdminEmail: getEnv("ADMIN_EMAIL", ""),
+
+		AccessTokenTTL:  getEnv("ACCESS_TOKEN_TTL", "15m"),
+		RefreshTokenTTL: getEnv("REFRESH_TOKEN_TTL", "720h"),
+
+		SMTPHost:         getEnv("SMTP_HOST", ""),
+		SMTPPort:         getEnv("SMTP_PORT", "587"),
+		SMTPUsername:     getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:     getEnv("SMTP_PASSWORD", ""),
+		MailFrom:         getEnv("MAIL_FROM", "Task Manager <noreply@localhost>"),
+		PasswordResetURL: getEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password?token="),
+		VerifyEmailURL:   getEnv("VERIFY_EMAIL_URL", "http://localhost:3000/verify-email?token="),
+
+		RequireVerifiedEmail: getEnv("REQUIRE_VERIFIED_EMAIL", "false"),
+
+		SubtaskOnComplete: getEnv("SUBTASK_ON_COMPLETE", "block"),
+		SubtaskOnDelete:   getEnv("SUBTASK_ON_DELETE", "cascade"),
+
+		SchedulerInterval:  getEnv("SCHEDULER_INTERVAL", "30s"),
+		ReminderWebhookURL: getEnv("REMINDER_WEBHOOK_URL", ""),
+
+		BlobStore:   getEnv("BLOB_STORE", "local"),
+		BlobDir:     getEnv("BLOB_DIR", "data/blobs"),
+		S3Endpoint:  getEnv("S3_ENDPOINT", ""),
+		S3Region:    getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:    getEnv("S3_BUCKET", ""),
+		S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+
+		AttachmentMaxBytes: getEnv("ATTACHMENT_MAX_BYTES", "10485760"),
+		AttachmentTypes:    getEnv("ATTACHMENT_TYPES", "image/png,image/jpeg,image/gif,image/webp,text/plain,application/pdf,application/zip,application/x-gzip"),
 	}
 }
 
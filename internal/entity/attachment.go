@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+type Attachment struct {
+	ID          int64     `json:"id"`
+	TaskID      int64     `json:"task_id"`
+	UploaderID  int64     `json:"uploader_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	StorageKey  string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AttachmentLimits — ограничения на загружаемые файлы. Тип определяется по
+// содержимому файла, а не по заголовку клиента.
+type AttachmentLimits struct {
+	MaxSize      int64
+	AllowedTypes []string
+}
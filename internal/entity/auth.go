@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+// TokenTTL — сроки жизни токена доступа (JWT) и refresh-токена.
+type TokenTTL struct {
+	Access  time.Duration
+	Refresh time.Duration
+}
+
+// AuthTokens выдаются при входе и обновлении: короткоживущий JWT и
+// одноразовый refresh-токен, по которому получают следующую пару.
+type AuthTokens struct {
+	Token            string    `json:"token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// RevokedToken — JWT, отозванный до истечения срока (ID — его jti).
+type RevokedToken struct {
+	ID        string
+	UserID    int64
+	ExpiresAt time.Time
+}
+
+// Session — один вход пользователя: устройство или браузер. Выданные в
+// сессии токены несут её id, поэтому отзыв сессии отзывает и их.
+type Session struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"-"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	RevokedAt  *time.Time `json:"-"`
+
+	// Current — сессия, из которой сделан запрос.
+	Current bool `json:"current"`
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+type ChecklistItem struct {
+	ID        int64     `json:"id"`
+	TaskID    int64     `json:"task_id"`
+	Text      string    `json:"text"`
+	Done      bool      `json:"done"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ChecklistProgress — сколько пунктов чек-листа отмечено; Summary — «3/7».
+type ChecklistProgress struct {
+	Done    int    `json:"done"`
+	Total   int    `json:"total"`
+	Summary string `json:"summary" example:"3/7"`
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// Comment хранит исходный Markdown в Body; BodyHTML — результат
+// безопасного рендера, заполняется при выдаче.
+type Comment struct {
+	ID        int64      `json:"id"`
+	TaskID    int64      `json:"task_id"`
+	AuthorID  int64      `json:"author_id"`
+	Body      string     `json:"body"`
+	BodyHTML  string     `json:"body_html"`
+	CreatedAt time.Time  `json:"created_at"`
+	EditedAt  *time.Time `json:"edited_at"`
+}
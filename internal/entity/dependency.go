@@ -0,0 +1,18 @@
+package entity
+
+// DependencyEdge — задача TaskID заблокирована задачей DependsOnID.
+type DependencyEdge struct {
+	TaskID      int64 `json:"task_id"`
+	DependsOnID int64 `json:"depends_on_id"`
+}
+
+// DependencyGraph — все задачи выше (блокирующие) и ниже (заблокированные)
+// по цепочке зависимостей от TaskID, рёбра между ними и порядок выполнения.
+type DependencyGraph struct {
+	TaskID     int64            `json:"task_id"`
+	Upstream   []int64          `json:"upstream"`
+	Downstream []int64          `json:"downstream"`
+	Tasks      []*Task          `json:"tasks"`
+	Edges      []DependencyEdge `json:"edges"`
+	Order      []int64          `json:"order"` // топологический порядок: блокирующие раньше
+}
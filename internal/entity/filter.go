@@ -0,0 +1,66 @@
+package entity
+
+// Типизированное дерево выражения фильтра списка задач. Строит его usecase
+// из строки filter, в SQL переводит репозиторий.
+
+type FilterField string
+
+const (
+	FilterID          FilterField = "id"
+	FilterProjectID   FilterField = "project_id"
+	FilterParentID    FilterField = "parent_id"
+	FilterTitle       FilterField = "title"
+	FilterDescription FilterField = "description"
+	FilterStatus      FilterField = "status"
+	FilterPriority    FilterField = "priority"
+	FilterStartAt     FilterField = "start_at"
+	FilterDueAt       FilterField = "due_at"
+	FilterCreatedAt   FilterField = "created_at"
+	FilterUpdatedAt   FilterField = "updated_at"
+	FilterLabel       FilterField = "label" // имя метки; OpEq — метка есть, OpNe — нет
+	// FilterStatusCategory — категория статуса по workflow владельца задачи,
+	// Value — StatusCategory. Так status:open работает и для чужих задач.
+	FilterStatusCategory FilterField = "status_category"
+)
+
+type FilterOp string
+
+const (
+	OpEq       FilterOp = "="
+	OpNe       FilterOp = "!="
+	OpLt       FilterOp = "<"
+	OpLe       FilterOp = "<="
+	OpGt       FilterOp = ">"
+	OpGe       FilterOp = ">="
+	OpContains FilterOp = "~"  // подстрока без учёта регистра
+	OpIn       FilterOp = "in" // Value — срез допустимых значений
+)
+
+type FilterNode interface {
+	filterNode()
+}
+
+type FilterAnd struct {
+	Left, Right FilterNode
+}
+
+type FilterOr struct {
+	Left, Right FilterNode
+}
+
+type FilterNot struct {
+	Expr FilterNode
+}
+
+// FilterCond — сравнение поля со значением. Тип Value зависит от поля:
+// int64, string, Priority, time.Time или []TaskStatus для OpIn.
+type FilterCond struct {
+	Field FilterField
+	Op    FilterOp
+	Value any
+}
+
+func (FilterAnd) filterNode()  {}
+func (FilterOr) filterNode()   {}
+func (FilterNot) filterNode()  {}
+func (FilterCond) filterNode() {}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// Label — метка рабочего пространства; OwnerID — её автор.
+type Label struct {
+	ID          int64     `json:"id"`
+	OwnerID     int64     `json:"owner_id"`
+	WorkspaceID int64     `json:"workspace_id"`
+	Name        string    `json:"name"`
+	Color       string    `json:"color"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,14 @@
+package entity
+
+// Email — письмо пользователю, обычный текст.
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// MailLinks — адреса, которые попадают в письма; токен дописывается в конец.
+type MailLinks struct {
+	PasswordReset string
+	VerifyEmail   string
+}
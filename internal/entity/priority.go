@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Priority хранится в БД числом, чтобы по нему можно было сортировать и
+// сравнивать; в JSON — именем. Нулевое значение означает «не задан».
+type Priority int16
+
+const (
+	PriorityLow Priority = iota + 1
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+var priorityNames = map[Priority]string{
+	PriorityLow:    "low",
+	PriorityMedium: "medium",
+	PriorityHigh:   "high",
+	PriorityUrgent: "urgent",
+}
+
+func (p Priority) Valid() bool {
+	_, ok := priorityNames[p]
+	return ok
+}
+
+func (p Priority) String() string {
+	if name, ok := priorityNames[p]; ok {
+		return name
+	}
+	return ""
+}
+
+func ParsePriority(s string) (Priority, error) {
+	for p, name := range priorityNames {
+		if name == s {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown priority %q", s)
+}
+
+func (p Priority) MarshalJSON() ([]byte, error) {
+	if p == 0 {
+		return []byte("null"), nil
+	}
+	return json.Marshal(p.String())
+}
+
+func (p *Priority) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == nil || *s == "" {
+		*p = 0
+		return nil
+	}
+	v, err := ParsePriority(*s)
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+type Project struct {
+	ID          int64     `json:"id"`
+	OwnerID     int64     `json:"owner_id"`
+	WorkspaceID int64     `json:"workspace_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
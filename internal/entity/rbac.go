@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+// Permission — разрешение на группу действий, «ресурс:действие». Разрешения
+// выдаются системными ролями пользователя и проверяются до бизнес-логики;
+// права на конкретную задачу (Role) проверяются уже после.
+type Permission string
+
+const (
+	PermTasksRead       Permission = "tasks:read"
+	PermTasksWrite      Permission = "tasks:write"
+	PermTasksDelete     Permission = "tasks:delete"
+	PermProjectsRead    Permission = "projects:read"
+	PermProjectsWrite   Permission = "projects:write"
+	PermProjectsDelete  Permission = "projects:delete"
+	PermLabelsRead      Permission = "labels:read"
+	PermLabelsWrite     Permission = "labels:write"
+	PermWorkflowRead    Permission = "workflow:read"
+	PermWorkflowWrite   Permission = "workflow:write"
+	PermWorkspacesRead  Permission = "workspaces:read"
+	PermWorkspacesWrite Permission = "workspaces:write"
+	PermRolesManage     Permission = "roles:manage"
+	PermUsersManage     Permission = "users:manage"
+)
+
+// Системные роли из миграции. Новые пользователи получают RoleNameUser.
+const (
+	RoleNameAdmin  = "admin"
+	RoleNameUser   = "user"
+	RoleNameReader = "reader"
+)
+
+// AccountRole — системная роль пользователя с её разрешениями.
+type AccountRole struct {
+	Name        string       `json:"name" example:"user"`
+	Description string       `json:"description"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// RoleGrant — системная роль, выданная пользователю.
+type RoleGrant struct {
+	Role      string    `json:"role"`
+	GrantedBy *int64    `json:"granted_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,320 @@
+package entity
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency — FREQ из RRULE (RFC 5545). Поддерживается подмножество правила:
+// FREQ, INTERVAL, BYDAY, COUNT и UNTIL.
+type Frequency string
+
+const (
+	FreqDaily   Frequency = "DAILY"
+	FreqWeekly  Frequency = "WEEKLY"
+	FreqMonthly Frequency = "MONTHLY"
+	FreqYearly  Frequency = "YEARLY"
+)
+
+// ByDay — элемент BYDAY: день недели и, для MONTHLY/YEARLY, его номер в
+// периоде (1MO — первый понедельник, -1FR — последняя пятница, 0 — каждый).
+type ByDay struct {
+	Weekday time.Weekday
+	N       int
+}
+
+type Recurrence struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []ByDay
+	Count    int        // 0 — без ограничения
+	Until    *time.Time // включительно
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// maxRecurrencePeriods ограничивает перебор периодов, если правило почти
+// никогда не срабатывает (например, 29 февраля с INTERVAL=3).
+const maxRecurrencePeriods = 10000
+
+// maxRecurrenceInterval — предел INTERVAL: больший шаг уводит серию за
+// maxRecurrenceYear уже на втором повторении.
+const maxRecurrenceInterval = 1000
+
+// maxRecurrenceYear — последний год, в котором генерируются повторения;
+// дальше даты не помещаются в формат UNTIL и в timestamp PostgreSQL.
+const maxRecurrenceYear = 9999
+
+// ParseRecurrence разбирает строку вида "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH".
+// Префикс "RRULE:" допускается.
+func ParseRecurrence(s string) (Recurrence, error) {
+	r := Recurrence{Interval: 1}
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return r, fmt.Errorf("empty rule")
+	}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || value == "" {
+			return r, fmt.Errorf("invalid part %q", part)
+		}
+		if seen[name] {
+			return r, fmt.Errorf("duplicate %s", name)
+		}
+		seen[name] = true
+
+		switch name {
+		case "FREQ":
+			r.Freq = Frequency(value)
+			switch r.Freq {
+			case FreqDaily, FreqWeekly, FreqMonthly, FreqYearly:
+			default:
+				return r, fmt.Errorf("unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > maxRecurrenceInterval {
+				return r, fmt.Errorf("invalid INTERVAL %q", value)
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return r, fmt.Errorf("invalid COUNT %q", value)
+			}
+			r.Count = n
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return r, fmt.Errorf("invalid UNTIL %q", value)
+			}
+			r.Until = &until
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				d, err := parseByDay(code)
+				if err != nil {
+					return r, err
+				}
+				r.ByDay = append(r.ByDay, d)
+			}
+		default:
+			return r, fmt.Errorf("unsupported part %s", name)
+		}
+	}
+
+	if r.Freq == "" {
+		return r, fmt.Errorf("FREQ is required")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return r, fmt.Errorf("COUNT and UNTIL are mutually exclusive")
+	}
+	for _, d := range r.ByDay {
+		if d.N != 0 && r.Freq != FreqMonthly && r.Freq != FreqYearly {
+			return r, fmt.Errorf("numbered BYDAY requires MONTHLY or YEARLY")
+		}
+		// в месяце не больше пяти одинаковых дней недели
+		if r.Freq == FreqMonthly && (d.N > 5 || d.N < -5) {
+			return r, fmt.Errorf("numbered BYDAY for MONTHLY must be within -5..5")
+		}
+	}
+	return r, nil
+}
+
+func parseUntil(v string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			if layout == "20060102" {
+				// дата без времени — весь день включительно
+				t = t.Add(24*time.Hour - time.Nanosecond)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("bad time")
+}
+
+func parseByDay(code string) (ByDay, error) {
+	code = strings.TrimSpace(code)
+	if len(code) < 2 {
+		return ByDay{}, fmt.Errorf("invalid BYDAY %q", code)
+	}
+	wd, ok := weekdayCodes[code[len(code)-2:]]
+	if !ok {
+		return ByDay{}, fmt.Errorf("invalid BYDAY %q", code)
+	}
+	d := ByDay{Weekday: wd}
+	if prefix := code[:len(code)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -53 || n > 53 {
+			return ByDay{}, fmt.Errorf("invalid BYDAY %q", code)
+		}
+		d.N = n
+	}
+	return d, nil
+}
+
+// String возвращает правило в каноническом виде; так оно и хранится в БД.
+func (r Recurrence) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			codes[i] = weekdayNames[d.Weekday]
+			if d.N != 0 {
+				codes[i] = strconv.Itoa(d.N) + codes[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences возвращает до n повторений серии, начатой в start, которые
+// строго позже after. Как и в RFC 5545, сам start считается первым
+// повторением и входит в COUNT. Время суток берётся из start.
+func (r Recurrence) Occurrences(start, after time.Time, n int) []time.Time {
+	out := []time.Time{}
+	if n <= 0 {
+		return out
+	}
+	emitted := 0
+	// emit учитывает повторение и сообщает, нужно ли продолжать перебор.
+	emit := func(t time.Time) bool {
+		if t.Year() > maxRecurrenceYear || (r.Until != nil && t.After(*r.Until)) {
+			return false
+		}
+		emitted++
+		if t.After(after) {
+			out = append(out, t)
+		}
+		return len(out) < n && (r.Count == 0 || emitted < r.Count)
+	}
+
+	if !emit(start) {
+		return out
+	}
+	for k := 0; k < maxRecurrencePeriods; k++ {
+		for _, t := range r.candidates(start, k*r.Interval) {
+			if !t.After(start) {
+				continue
+			}
+			if !emit(t) {
+				return out
+			}
+		}
+	}
+	return out
+}
+
+// candidates — даты-кандидаты в периоде, отстоящем от периода start на
+// offset единиц FREQ, по возрастанию.
+func (r Recurrence) candidates(start time.Time, offset int) []time.Time {
+	y, m, d := start.Date()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+	}
+
+	var days []time.Time
+	switch r.Freq {
+	case FreqDaily:
+		day := at(y, m, d+offset)
+		if r.matchesWeekday(day.Weekday()) {
+			days = append(days, day)
+		}
+	case FreqWeekly:
+		// неделя начинается с понедельника (WKST=MO)
+		monday := d - (int(start.Weekday())+6)%7 + 7*offset
+		if len(r.ByDay) == 0 {
+			return []time.Time{at(y, m, d+7*offset)}
+		}
+		for i := 0; i < 7; i++ {
+			day := at(y, m, monday+i)
+			if r.matchesWeekday(day.Weekday()) {
+				days = append(days, day)
+			}
+		}
+	case FreqMonthly:
+		first := at(y, m+time.Month(offset), 1)
+		if len(r.ByDay) == 0 {
+			if day := at(first.Year(), first.Month(), d); day.Day() == d {
+				days = append(days, day)
+			}
+			return days
+		}
+		days = r.weekdaysIn(first, first.AddDate(0, 1, 0))
+	case FreqYearly:
+		if len(r.ByDay) == 0 {
+			if day := at(y+offset, m, d); day.Month() == m {
+				days = append(days, day)
+			}
+			return days
+		}
+		first := at(y+offset, time.January, 1)
+		days = r.weekdaysIn(first, first.AddDate(1, 0, 0))
+	}
+	return days
+}
+
+func (r Recurrence) matchesWeekday(wd time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, d := range r.ByDay {
+		if d.Weekday == wd {
+			return true
+		}
+	}
+	return false
+}
+
+// weekdaysIn выбирает дни из [from, to) по BYDAY с учётом номера дня в периоде.
+func (r Recurrence) weekdaysIn(from, to time.Time) []time.Time {
+	byWeekday := map[time.Weekday][]time.Time{}
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		byWeekday[day.Weekday()] = append(byWeekday[day.Weekday()], day)
+	}
+
+	picked := map[time.Time]bool{}
+	var days []time.Time
+	add := func(t time.Time) {
+		if !picked[t] {
+			picked[t] = true
+			days = append(days, t)
+		}
+	}
+	for _, d := range r.ByDay {
+		all := byWeekday[d.Weekday]
+		switch {
+		case d.N == 0:
+			for _, t := range all {
+				add(t)
+			}
+		case d.N > 0 && d.N <= len(all):
+			add(all[d.N-1])
+		case d.N < 0 && -d.N <= len(all):
+			add(all[len(all)+d.N])
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
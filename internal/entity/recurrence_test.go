@@ -0,0 +1,246 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 9, 30, 0, 0, time.UTC)
+}
+
+func TestParseRecurrence(t *testing.T) {
+	until := time.Date(2026, 12, 31, 23, 59, 59, int(time.Second-time.Nanosecond), time.UTC)
+	tests := []struct {
+		src   string
+		want  Recurrence
+		canon string
+	}{
+		{"FREQ=DAILY", Recurrence{Freq: FreqDaily, Interval: 1}, "FREQ=DAILY"},
+		{
+			"RRULE:freq=weekly; interval=2 ;byday=MO,th",
+			Recurrence{Freq: FreqWeekly, Interval: 2, ByDay: []ByDay{{time.Monday, 0}, {time.Thursday, 0}}},
+			"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+		},
+		{
+			"FREQ=MONTHLY;BYDAY=-1FR,+2TU;COUNT=3",
+			Recurrence{Freq: FreqMonthly, Interval: 1, ByDay: []ByDay{{time.Friday, -1}, {time.Tuesday, 2}}, Count: 3},
+			"FREQ=MONTHLY;BYDAY=-1FR,2TU;COUNT=3",
+		},
+		{"FREQ=MONTHLY;BYDAY=5SU,-5SA", Recurrence{Freq: FreqMonthly, Interval: 1, ByDay: []ByDay{{time.Sunday, 5}, {time.Saturday, -5}}}, "FREQ=MONTHLY;BYDAY=5SU,-5SA"},
+		{"FREQ=YEARLY;BYDAY=53MO", Recurrence{Freq: FreqYearly, Interval: 1, ByDay: []ByDay{{time.Monday, 53}}}, "FREQ=YEARLY;BYDAY=53MO"},
+		{"FREQ=YEARLY;UNTIL=20261231", Recurrence{Freq: FreqYearly, Interval: 1, Until: &until}, "FREQ=YEARLY;UNTIL=20261231T235959Z"},
+		{"FREQ=DAILY;INTERVAL=1000", Recurrence{Freq: FreqDaily, Interval: 1000}, "FREQ=DAILY;INTERVAL=1000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			got, err := ParseRecurrence(tt.src)
+			if err != nil {
+				t.Fatalf("ParseRecurrence: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got  %+v\nwant %+v", got, tt.want)
+			}
+			if s := got.String(); s != tt.canon {
+				t.Fatalf("String %q, want %q", s, tt.canon)
+			}
+			again, err := ParseRecurrence(got.String())
+			if err != nil || again.String() != tt.canon {
+				t.Fatalf("canonical form does not round-trip: %q, %v", again.String(), err)
+			}
+		})
+	}
+}
+
+func TestParseRecurrenceErrors(t *testing.T) {
+	for _, src := range []string{
+		"",
+		"RRULE:",
+		"FREQ",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;BYMONTH=1",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;INTERVAL=1001",
+		"FREQ=DAILY;INTERVAL=x",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20260101",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=DAILY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=MONTHLY;BYDAY=-6MO",
+		"FREQ=YEARLY;BYDAY=54MO",
+	} {
+		if r, err := ParseRecurrence(src); err == nil {
+			t.Errorf("%q: expected error, got %+v", src, r)
+		}
+	}
+}
+
+func TestParseByDay(t *testing.T) {
+	tests := []struct {
+		code string
+		want ByDay
+		ok   bool
+	}{
+		{"MO", ByDay{time.Monday, 0}, true},
+		{" SU ", ByDay{time.Sunday, 0}, true},
+		{"1MO", ByDay{time.Monday, 1}, true},
+		{"+2TU", ByDay{time.Tuesday, 2}, true},
+		{"-1FR", ByDay{time.Friday, -1}, true},
+		{"53SA", ByDay{time.Saturday, 53}, true},
+		{"-53SA", ByDay{time.Saturday, -53}, true},
+		{"0MO", ByDay{}, false},
+		{"54MO", ByDay{}, false},
+		{"-54MO", ByDay{}, false},
+		{"AMO", ByDay{}, false},
+		{"1XX", ByDay{}, false},
+		{"M", ByDay{}, false},
+		{"", ByDay{}, false},
+	}
+	for _, tt := range tests {
+		got, err := parseByDay(tt.code)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseByDay(%q) = %+v, %v; want %+v, ok=%v", tt.code, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	mon := date(2026, time.January, 5) // понедельник
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		after time.Time
+		n     int
+		want  []time.Time
+	}{
+		{
+			name: "daily", rule: "FREQ=DAILY", start: mon, after: mon, n: 3,
+			want: []time.Time{date(2026, 1, 6), date(2026, 1, 7), date(2026, 1, 8)},
+		},
+		{
+			name: "start counts toward COUNT", rule: "FREQ=DAILY;INTERVAL=2;COUNT=3", start: mon, n: 10,
+			want: []time.Time{mon, date(2026, 1, 7), date(2026, 1, 9)},
+		},
+		{
+			name: "COUNT after skipped occurrences", rule: "FREQ=DAILY;COUNT=3", start: mon, after: date(2026, 1, 6), n: 10,
+			want: []time.Time{date(2026, 1, 7)},
+		},
+		{
+			name: "UNTIL date is inclusive", rule: "FREQ=DAILY;UNTIL=20260107", start: mon, n: 10,
+			want: []time.Time{mon, date(2026, 1, 6), date(2026, 1, 7)},
+		},
+		{
+			name: "weekly by day", rule: "FREQ=WEEKLY;BYDAY=MO,TH", start: mon, after: mon, n: 4,
+			want: []time.Time{date(2026, 1, 8), date(2026, 1, 12), date(2026, 1, 15), date(2026, 1, 19)},
+		},
+		{
+			name: "every other week", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,FR", start: mon, n: 4,
+			want: []time.Time{mon, date(2026, 1, 6), date(2026, 1, 9), date(2026, 1, 20)},
+		},
+		{
+			name: "monthly skips short months", rule: "FREQ=MONTHLY", start: date(2026, 1, 31), after: date(2026, 1, 31), n: 3,
+			want: []time.Time{date(2026, 3, 31), date(2026, 5, 31), date(2026, 7, 31)},
+		},
+		{
+			name: "last friday", rule: "FREQ=MONTHLY;BYDAY=-1FR", start: date(2026, 1, 30), after: date(2026, 1, 30), n: 3,
+			want: []time.Time{date(2026, 2, 27), date(2026, 3, 27), date(2026, 4, 24)},
+		},
+		{
+			name: "fifth monday", rule: "FREQ=MONTHLY;BYDAY=5MO", start: mon, after: mon, n: 2,
+			want: []time.Time{date(2026, 3, 30), date(2026, 6, 29)},
+		},
+		{
+			name: "leap day", rule: "FREQ=YEARLY", start: date(2024, 2, 29), after: date(2024, 2, 29), n: 2,
+			want: []time.Time{date(2028, 2, 29), date(2032, 2, 29)},
+		},
+		{
+			name: "stops at year 9999", rule: "FREQ=YEARLY;INTERVAL=1000", start: mon, after: mon, n: 10,
+			want: []time.Time{
+				date(3026, 1, 5), date(4026, 1, 5), date(5026, 1, 5), date(6026, 1, 5),
+				date(7026, 1, 5), date(8026, 1, 5), date(9026, 1, 5),
+			},
+		},
+		{
+			name: "last day of year 9999", rule: "FREQ=DAILY", start: date(9999, 12, 30), after: date(9999, 12, 30), n: 5,
+			want: []time.Time{date(9999, 12, 31)},
+		},
+		{
+			name: "zero n", rule: "FREQ=DAILY", start: mon, n: 0,
+			want: []time.Time{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseRecurrence(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := r.Occurrences(tt.start, tt.after, tt.n)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got  %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	wed := date(2026, time.January, 7)
+	tests := []struct {
+		name   string
+		rule   string
+		start  time.Time
+		offset int
+		want   []time.Time
+	}{
+		{
+			name: "week starts on monday", rule: "FREQ=WEEKLY;BYDAY=SU,MO", start: wed,
+			want: []time.Time{date(2026, 1, 5), date(2026, 1, 11)},
+		},
+		{
+			name: "weekly without BYDAY", rule: "FREQ=WEEKLY", start: wed, offset: 3,
+			want: []time.Time{date(2026, 1, 28)},
+		},
+		{
+			name: "daily filtered by weekday", rule: "FREQ=DAILY;BYDAY=MO", start: wed, offset: 1,
+			want: nil,
+		},
+		{
+			name: "no 31st in february", rule: "FREQ=MONTHLY", start: date(2026, 1, 31), offset: 1,
+			want: nil,
+		},
+		{
+			name: "monthly offset crosses year", rule: "FREQ=MONTHLY", start: date(2026, 11, 15), offset: 3,
+			want: []time.Time{date(2027, 2, 15)},
+		},
+		{
+			name: "duplicate days are merged", rule: "FREQ=MONTHLY;BYDAY=MO,1MO", start: wed,
+			want: []time.Time{date(2026, 1, 5), date(2026, 1, 12), date(2026, 1, 19), date(2026, 1, 26)},
+		},
+		{
+			name: "first and last monday of year", rule: "FREQ=YEARLY;BYDAY=-1MO,1MO", start: wed,
+			want: []time.Time{date(2026, 1, 5), date(2026, 12, 28)},
+		},
+		{
+			name: "missing fifth weekday", rule: "FREQ=MONTHLY;BYDAY=5MO", start: wed,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseRecurrence(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := r.candidates(tt.start, tt.offset)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got  %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Reminder срабатывает либо за OffsetMinutes минут до due_at задачи, либо
+// в RemindAt — задано ровно одно из двух. FireAt вычисляется из них и
+// равен nil, если у задачи нет срока.
+type Reminder struct {
+	ID            int64      `json:"id"`
+	TaskID        int64      `json:"task_id"`
+	OffsetMinutes *int       `json:"offset_minutes"`
+	RemindAt      *time.Time `json:"remind_at"`
+	FireAt        *time.Time `json:"fire_at"`
+	SentAt        *time.Time `json:"sent_at"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// ReminderDelivery — сработавшее напоминание вместе с задачей, к которой оно относится.
+type ReminderDelivery struct {
+	Reminder *Reminder
+	Task     *Task
+}
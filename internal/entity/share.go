@@ -0,0 +1,50 @@
+package entity
+
+import "time"
+
+// Role — уровень доступа к задаче или проекту. Каждая следующая роль
+// включает права предыдущей: viewer читает, editor меняет содержимое,
+// owner ещё и удаляет, переносит и управляет доступом.
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleOwner  Role = "owner"
+)
+
+var roleRank = map[Role]int{RoleViewer: 1, RoleEditor: 2, RoleOwner: 3}
+
+func (r Role) Valid() bool {
+	return roleRank[r] > 0
+}
+
+// Allows сообщает, достаточно ли роли r для действия, требующего min.
+func (r Role) Allows(min Role) bool {
+	return r.Valid() && roleRank[r] >= roleRank[min]
+}
+
+// ShareTarget — к чему выдан доступ.
+type ShareTarget string
+
+const (
+	ShareTask    ShareTarget = "task"
+	ShareProject ShareTarget = "project"
+)
+
+// Share — доступ пользователя к задаче или проекту.
+type Share struct {
+	UserID    int64     `json:"user_id"`
+	Email     string    `json:"email"`
+	Role      Role      `json:"role" enums:"viewer,editor,owner"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Scope — чьи задачи или проекты попадают в список.
+type Scope string
+
+const (
+	ScopeOwn    Scope = "own"    // свои (по умолчанию)
+	ScopeShared Scope = "shared" // чужие, к которым выдан доступ
+	ScopeAll    Scope = "all"    // свои и чужие вместе
+)
@@ -0,0 +1,39 @@
+package entity
+
+import "fmt"
+
+// SubtaskPolicy — что происходит с подзадачами, когда родителя выполняют
+// или удаляют.
+type SubtaskPolicy string
+
+const (
+	SubtaskCascade SubtaskPolicy = "cascade" // то же действие применяется ко всем потомкам
+	SubtaskBlock   SubtaskPolicy = "block"   // действие запрещено, пока есть (открытые) подзадачи
+	SubtaskOrphan  SubtaskPolicy = "orphan"  // подзадачи отвязываются от родителя
+)
+
+func ParseSubtaskPolicy(s string) (SubtaskPolicy, error) {
+	switch p := SubtaskPolicy(s); p {
+	case SubtaskCascade, SubtaskBlock, SubtaskOrphan:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown subtask policy %q", s)
+}
+
+type SubtaskPolicies struct {
+	OnComplete SubtaskPolicy
+	OnDelete   SubtaskPolicy
+}
+
+// TaskProgress — доля выполненных прямых подзадач. Отменённые не считаются.
+type TaskProgress struct {
+	Total   int `json:"total"`
+	Done    int `json:"done"`
+	Percent int `json:"percent"`
+}
+
+// TaskTree — задача со всеми потомками.
+type TaskTree struct {
+	*Task
+	Subtasks []*TaskTree `json:"subtasks"`
+}
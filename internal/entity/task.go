@@ -3,11 +3,133 @@ package entity
 import "time"
 
 type Task struct {
-	ID          int64     `json:"id"`
-	OwnerID     int64     `json:"owner_id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Status      bool      `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int64      `json:"id"`
+	OwnerID     int64      `json:"owner_id"`
+	WorkspaceID int64      `json:"workspace_id"`
+	AssigneeID  *int64     `json:"assignee_id"`
+	ProjectID   *int64     `json:"project_id"`
+	ParentID    *int64     `json:"parent_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      TaskStatus `json:"status"`
+	Priority    Priority   `json:"priority" swaggertype:"string" enums:"low,medium,high,urgent"`
+	StartAt     *time.Time `json:"start_at"`
+	DueAt       *time.Time `json:"due_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Labels      []Label    `json:"labels"`
+
+	// CommentCount — число комментариев, Checklist — прогресс чек-листа (nil,
+	// если пунктов нет); считаются при чтении.
+	CommentCount int64              `json:"comment_count"`
+	Checklist    *ChecklistProgress `json:"checklist,omitempty"`
+
+	// Recurrence — правило повторения (RRULE), RecurrenceStart — начало серии
+	// (DTSTART). Когда задачу выполняют, правило переходит к следующему повторению.
+	Recurrence      string     `json:"recurrence,omitempty" example:"FREQ=WEEKLY;BYDAY=MO"`
+	RecurrenceStart *time.Time `json:"recurrence_start,omitempty"`
+	// NextOccurrence заполняется только в ответе на выполнение повторяющейся задачи.
+	NextOccurrence *Task `json:"next_occurrence,omitempty"`
+
+	// Progress считается по прямым подзадачам; nil, если их нет.
+	Progress *TaskProgress `json:"progress,omitempty"`
+}
+
+// StatusEffects — изменения, которые сохраняются в одной транзакции со
+// сменой статуса задачи.
+type StatusEffects struct {
+	// Next — следующее повторение. Репозиторий заполняет его id или
+	// обнуляет, если правило уже перешло к другому повторению.
+	Next *Task
+	// Cascade — открытые потомки, которые закрываются вместе с задачей
+	// (SUBTASK_ON_COMPLETE=cascade).
+	Cascade []CascadedStatus
+	// DetachChildren — сделать прямые подзадачи корневыми (orphan).
+	DetachChildren bool
+}
+
+// CascadedStatus — подзадача, которая переходит из From в Task.Status
+// вместе с родителем, и её следующее повторение.
+type CascadedStatus struct {
+	Task *Task
+	From TaskStatus
+	Next *Task
+}
+
+// TaskSortField — поле, по которому разрешено сортировать список задач.
+type TaskSortField string
+
+const (
+	SortByID        TaskSortField = "id"
+	SortByTitle     TaskSortField = "title"
+	SortByPriority  TaskSortField = "priority"
+	SortByStartAt   TaskSortField = "start_at"
+	SortByDueAt     TaskSortField = "due_at"
+	SortByCreatedAt TaskSortField = "created_at"
+	SortByUpdatedAt TaskSortField = "updated_at"
+)
+
+type TaskSort struct {
+	Field TaskSortField
+	Desc  bool
+}
+
+// TaskQuery — условия выборки и порядок списка задач.
+type TaskQuery struct {
+	Scope Scope
+
+	Overdue   bool       // срок прошёл, а задача открыта по workflow её владельца
+	DueBefore *time.Time // срок раньше указанного момента
+
+	ProjectID  *int64     // только задачи проекта
+	AssigneeID *int64     // только задачи этого исполнителя
+	ParentID   *int64     // только прямые подзадачи этой задачи
+	Labels     []string   // у задачи есть все метки с этими именами
+	Filter     FilterNode // выражение из параметра filter
+
+	Sort []TaskSort
+
+	Limit int
+	After *TaskCursor // продолжить выдачу после этой позиции
+}
+
+// OrderKeys возвращает фактический порядок выдачи: Sort, замкнутый по id,
+// чтобы порядок был однозначным и по нему можно было листать курсором.
+func (q TaskQuery) OrderKeys() []TaskSort {
+	keys := make([]TaskSort, 0, len(q.Sort)+1)
+	for _, s := range q.Sort {
+		keys = append(keys, s)
+		if s.Field == SortByID {
+			return keys
+		}
+	}
+	return append(keys, TaskSort{Field: SortByID, Desc: true})
+}
+
+// TaskCursor — позиция в списке: значения ключей OrderKeys у последней
+// выданной задачи, в том же порядке.
+type TaskCursor struct {
+	Values []any
+}
+
+// PageRequest — параметры постраничной выдачи, как их прислал клиент.
+type PageRequest struct {
+	Limit     int
+	Cursor    string
+	WithTotal bool
+}
+
+type TaskPage struct {
+	Tasks      []*Task
+	NextCursor string
+	Total      *int64
+}
+
+// TaskSearchHit — задача, найденная полнотекстовым поиском. Фрагменты —
+// HTML: текст экранирован, совпадения обёрнуты в <mark>.
+type TaskSearchHit struct {
+	Task               *Task   `json:"task"`
+	Rank               float64 `json:"rank"`
+	TitleSnippet       string  `json:"title_snippet"`
+	DescriptionSnippet string  `json:"description_snippet"`
 }
@@ -10,4 +10,31 @@ type User struct {
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 	Tasks        []Task    `json:"tasks,omitempty"`
+
+	// DisabledAt — когда администратор отключил аккаунт; MustResetPassword —
+	// вход запрещён, пока пользователь не задаст новый пароль.
+	DisabledAt        *time.Time `json:"disabled_at,omitempty"`
+	MustResetPassword bool       `json:"must_reset_password"`
+	// EmailVerifiedAt — когда пользователь подтвердил почту; nil — ещё нет.
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+
+	// TokensValidAfter — JWT, выданные раньше, не принимаются.
+	TokensValidAfter *time.Time `json:"-"`
+}
+
+// UserTaskCounts — задачи пользователя во всех пространствах по статусам.
+type UserTaskCounts struct {
+	UserID   int64                `json:"user_id"`
+	Total    int64                `json:"total"`
+	Overdue  int64                `json:"overdue"`
+	Assigned int64                `json:"assigned"`
+	ByStatus map[TaskStatus]int64 `json:"by_status"`
+}
+
+// PasswordReset — выписанный сброс пароля. Token есть только в ответе на
+// создание: в базе хранится хэш.
+type PasswordReset struct {
+	UserID    int64     `json:"user_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
@@ -0,0 +1,131 @@
+package entity
+
+// TaskStatus — ключ состояния задачи в workflow (встроенного или пользовательского).
+type TaskStatus string
+
+const (
+	StatusTodo       TaskStatus = "todo"
+	StatusInProgress TaskStatus = "in_progress"
+	StatusBlocked    TaskStatus = "blocked"
+	StatusDone       TaskStatus = "done"
+	StatusCancelled  TaskStatus = "cancelled"
+)
+
+// StatusCategory определяет, что состояние означает для остальной логики:
+// задача открыта, выполнена или отменена.
+type StatusCategory string
+
+const (
+	CategoryOpen      StatusCategory = "open"
+	CategoryDone      StatusCategory = "done"
+	CategoryCancelled StatusCategory = "cancelled"
+)
+
+func (c StatusCategory) Valid() bool {
+	switch c {
+	case CategoryOpen, CategoryDone, CategoryCancelled:
+		return true
+	}
+	return false
+}
+
+type WorkflowState struct {
+	Key      TaskStatus     `json:"key"`
+	Name     string         `json:"name"`
+	Category StatusCategory `json:"category"`
+	Custom   bool           `json:"custom"`
+}
+
+type WorkflowTransition struct {
+	From   TaskStatus `json:"from"`
+	To     TaskStatus `json:"to"`
+	Custom bool       `json:"custom"`
+}
+
+type Workflow struct {
+	States      []WorkflowState      `json:"states"`
+	Transitions []WorkflowTransition `json:"transitions"`
+}
+
+var builtinStates = []WorkflowState{
+	{Key: StatusTodo, Name: "К выполнению", Category: CategoryOpen},
+	{Key: StatusInProgress, Name: "В работе", Category: CategoryOpen},
+	{Key: StatusBlocked, Name: "Заблокирована", Category: CategoryOpen},
+	{Key: StatusDone, Name: "Выполнена", Category: CategoryDone},
+	{Key: StatusCancelled, Name: "Отменена", Category: CategoryCancelled},
+}
+
+var builtinTransitions = []WorkflowTransition{
+	{From: StatusTodo, To: StatusInProgress},
+	{From: StatusTodo, To: StatusBlocked},
+	{From: StatusTodo, To: StatusDone},
+	{From: StatusTodo, To: StatusCancelled},
+	{From: StatusInProgress, To: StatusTodo},
+	{From: StatusInProgress, To: StatusBlocked},
+	{From: StatusInProgress, To: StatusDone},
+	{From: StatusInProgress, To: StatusCancelled},
+	{From: StatusBlocked, To: StatusTodo},
+	{From: StatusBlocked, To: StatusInProgress},
+	{From: StatusBlocked, To: StatusCancelled},
+	{From: StatusDone, To: StatusTodo},
+	{From: StatusCancelled, To: StatusTodo},
+}
+
+// BuiltinStates возвращает встроенные состояния — общие для всех workflow.
+func BuiltinStates() []WorkflowState {
+	return append([]WorkflowState(nil), builtinStates...)
+}
+
+// IsBuiltinStatus сообщает, является ли ключ одним из встроенных состояний.
+func IsBuiltinStatus(key TaskStatus) bool {
+	for _, s := range builtinStates {
+		if s.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// NewWorkflow собирает workflow пользователя: встроенные состояния и переходы
+// плюс его собственные.
+func NewWorkflow(states []WorkflowState, transitions []WorkflowTransition) *Workflow {
+	w := &Workflow{
+		States:      make([]WorkflowState, 0, len(builtinStates)+len(states)),
+		Transitions: make([]WorkflowTransition, 0, len(builtinTransitions)+len(transitions)),
+	}
+	w.States = append(w.States, builtinStates...)
+	w.States = append(w.States, states...)
+	w.Transitions = append(w.Transitions, builtinTransitions...)
+	w.Transitions = append(w.Transitions, transitions...)
+	return w
+}
+
+func (w *Workflow) State(key TaskStatus) (WorkflowState, bool) {
+	for _, s := range w.States {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return WorkflowState{}, false
+}
+
+func (w *Workflow) CanTransition(from, to TaskStatus) bool {
+	for _, t := range w.Transitions {
+		if t.From == from && t.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDone — состояние относится к категории «выполнено».
+func (w *Workflow) IsDone(key TaskStatus) bool {
+	s, ok := w.State(key)
+	return ok && s.Category == CategoryDone
+}
+
+// IsClosed — задача выполнена или отменена.
+func (w *Workflow) IsClosed(key TaskStatus) bool {
+	s, ok := w.State(key)
+	return ok && s.Category != CategoryOpen
+}
@@ -0,0 +1,56 @@
+package entity
+
+import "time"
+
+// WorkspaceRole — роль участника рабочего пространства. admin управляет
+// участниками и получает права владельца на все задачи и проекты
+// пространства, member заводит свои задачи и проекты, guest работает только
+// с тем, к чему ему выдали доступ.
+type WorkspaceRole string
+
+const (
+	WorkspaceAdmin  WorkspaceRole = "admin"
+	WorkspaceMember WorkspaceRole = "member"
+	WorkspaceGuest  WorkspaceRole = "guest"
+)
+
+func (r WorkspaceRole) Valid() bool {
+	switch r {
+	case WorkspaceAdmin, WorkspaceMember, WorkspaceGuest:
+		return true
+	}
+	return false
+}
+
+// Workspace — рабочее пространство. Задачи и проекты принадлежат ровно
+// одному пространству и за его пределами не видны.
+type Workspace struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Personal  bool      `json:"personal"`
+	CreatedAt time.Time `json:"created_at"`
+	// Role — роль текущего пользователя; заполняется в списке пространств.
+	Role WorkspaceRole `json:"role,omitempty" enums:"admin,member,guest"`
+}
+
+// Membership — участник пространства.
+type Membership struct {
+	WorkspaceID int64         `json:"workspace_id"`
+	UserID      int64         `json:"user_id"`
+	Email       string        `json:"email"`
+	Role        WorkspaceRole `json:"role" enums:"admin,member,guest"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// Invitation — приглашение в пространство. Token отдаётся только при
+// создании: в базе хранится его хэш.
+type Invitation struct {
+	ID          int64         `json:"id"`
+	WorkspaceID int64         `json:"workspace_id"`
+	Email       string        `json:"email"`
+	Role        WorkspaceRole `json:"role" enums:"admin,member,guest"`
+	Token       string        `json:"token,omitempty"`
+	ExpiresAt   time.Time     `json:"expires_at"`
+	AcceptedAt  *time.Time    `json:"accepted_at,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
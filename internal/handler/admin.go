@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"app/internal/usecase"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// writeAdminError переводит ошибку usecase администрирования в HTTP-ответ.
+func writeAdminError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// @Summary      Пользователи
+// @Description  Поиск по подстроке почты. Нужно разрешение users:manage.
+// @Security     BearerAuth
+// @Tags         admin
+// @Produce      json
+// @Param        q       query string false "подстрока почты"
+// @Param        limit   query int    false "сколько пользователей (по умолчанию 50, максимум 200)"
+// @Param        offset  query int    false "сколько пользователей пропустить"
+// @Success      200 {object} UsersResponse
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /admin/users [get]
+func (h *Handler) adminListUsers(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+		return
+	}
+
+	users, total, err := h.AdminUseCase.ListUsers(c.Request.Context(), c.Query("q"), limit, offset)
+	if err != nil {
+		writeAdminError(c, err, "failed to list users")
+		return
+	}
+	c.JSON(http.StatusOK, UsersResponse{Users: users, Total: total})
+}
+
+// @Summary      Один пользователь
+// @Description  Нужно разрешение users:manage.
+// @Security     BearerAuth
+// @Tags         admin
+// @Produce      json
+// @Param        id   path int true "User ID"
+// @Success      200 {object} entity.User
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /admin/users/{id} [get]
+func (h *Handler) adminGetUser(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	user, err := h.AdminUseCase.GetUser(c.Request.Context(), id)
+	if err != nil {
+		writeAdminError(c, err, "failed to get user")
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// @Summary      Задачи пользователя по статусам
+// @Description  Сколько задач у пользователя всего, просрочено, назначено ему и в каждом статусе. Нужно разрешение users:manage.
+// @Security     BearerAuth
+// @Tags         admin
+// @Produce      json
+// @Param        id   path int true "User ID"
+// @Success      200 {object} entity.UserTaskCounts
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /admin/users/{id}/task-counts [get]
+func (h *Handler) adminUserTaskCounts(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	counts, err := h.AdminUseCase.TaskCounts(c.Request.Context(), id)
+	if err != nil {
+		writeAdminError(c, err, "failed to count tasks")
+		return
+	}
+	c.JSON(http.StatusOK, counts)
+}
+
+// @Summary      Отключить аккаунт
+// @Description  Отключённый пользователь не может войти, его токены перестают приниматься. Себя отключить нельзя. Нужно разрешение users:manage.
+// @Security     BearerAuth
+// @Tags         admin
+// @Produce      json
+// @Param        id   path int true "User ID"
+// @Success      200 {object} entity.User
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /admin/users/{id}/disable [post]
+func (h *Handler) adminDisableUser(c *gin.Context) {
+	h.adminSetDisabled(c, true)
+}
+
+// @Summary      Включить аккаунт
+// @Description  Нужно разрешение users:manage.
+// @Security     BearerAuth
+// @Tags         admin
+// @Produce      json
+// @Param        id   path int true "User ID"
+// @Success      200 {object} entity.User
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /admin/users/{id}/enable [post]
+func (h *Handler) adminEnableUser(c *gin.Context) {
+	h.adminSetDisabled(c, false)
+}
+
+func (h *Handler) adminSetDisabled(c *gin.Context, disabled bool) {
+	adminID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	user, err := h.AdminUseCase.SetDisabled(c.Request.Context(), adminID, id, disabled)
+	if err != nil {
+		writeAdminError(c, err, "failed to update user")
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// @Summary      Потребовать смену пароля
+// @Description  Вход по текущему паролю блокируется. Возвращает одноразовый токен сброса на 24 часа; администратор передаёт его пользователю, тот задаёт пароль через /auth/password/reset. Нужно разрешение users:manage.
+// @Security     BearerAuth
+// @Tags         admin
+// @Produce      json
+// @Param        id   path int true "User ID"
+// @Success      200 {object} entity.PasswordReset
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /admin/users/{id}/password-reset [post]
+func (h *Handler) adminForcePasswordReset(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	reset, err := h.AdminUseCase.ForcePasswordReset(c.Request.Context(), id)
+	if err != nil {
+		writeAdminError(c, err, "failed to reset password")
+		return
+	}
+	c.JSON(http.StatusOK, reset)
+}
+
+// @Summary      Удалить пользователя
+// @Description  Удаляет пользователя вместе с его задачами и проектами. Себя удалить нельзя. Нужно разрешение users:manage.
+// @Security     BearerAuth
+// @Tags         admin
+// @Param        id   path int true "User ID"
+// @Success      204
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /admin/users/{id} [delete]
+func (h *Handler) adminDeleteUser(c *gin.Context) {
+	adminID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.AdminUseCase.DeleteUser(c.Request.Context(), adminID, id); err != nil {
+		writeAdminError(c, err, "failed to delete user")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
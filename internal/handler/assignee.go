@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary      Назначить исполнителя
+// @Description  Исполнителем можно назначить только пользователя с доступом к задаче; assignee_id: null снимает назначение. Исполнитель может менять статус задачи, но не удалять её.
+// @Security     BearerAuth
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        id   path int true "Task ID"
+// @Param        request body SetAssigneeRequest true "payload"
+// @Success      200 {object} entity.Task
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/assignee [put]
+func (h *Handler) setAssignee(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var r SetAssigneeRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	task, err := h.TaskUseCase.SetAssignee(c.Request.Context(), taskID, userID, r.AssigneeID)
+	if err != nil {
+		writeTaskError(c, err, "failed to set assignee")
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
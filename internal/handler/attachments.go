@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"mime"
+	"net/http"
+)
+
+// multipartOverhead — запас на заголовки multipart сверх максимального размера файла.
+const multipartOverhead = 1 << 20
+
+// @Summary      Загрузить вложение
+// @Description  multipart/form-data, поле file. Размер и тип (по содержимому) проверяются на сервере.
+// @Security     BearerAuth
+// @Tags         attachments
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id    path     int  true "Task ID"
+// @Param        file  formData file true "файл"
+// @Success      200 {object} entity.Attachment
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      413 {object} map[string]string
+// @Failure      415 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/attachments [post]
+func (h *Handler) uploadAttachment(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.AttachmentUseCase.MaxSize()+multipartOverhead)
+	fh, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	file, err := fh.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.AttachmentUseCase.Upload(c.Request.Context(), taskID, userID, fh.Filename, fh.Size, file)
+	if err != nil {
+		writeTaskError(c, err, "failed to upload attachment")
+		return
+	}
+	c.JSON(http.StatusOK, attachment)
+}
+
+// @Summary      Вложения задачи
+// @Security     BearerAuth
+// @Tags         attachments
+// @Produce      json
+// @Param        id   path int true "Task ID"
+// @Success      200 {object} AttachmentsResponse
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/attachments [get]
+func (h *Handler) getAttachments(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	attachments, err := h.AttachmentUseCase.ListAttachments(c.Request.Context(), taskID, userID)
+	if err != nil {
+		writeTaskError(c, err, "failed to get attachments")
+		return
+	}
+	c.JSON(http.StatusOK, AttachmentsResponse{Attachments: attachments})
+}
+
+// @Summary      Скачать вложение
+// @Description  Поддерживает Range и условные запросы (If-Modified-Since, If-Range)
+// @Security     BearerAuth
+// @Tags         attachments
+// @Produce      octet-stream
+// @Param        id             path int true "Task ID"
+// @Param        attachment_id  path int true "Attachment ID"
+// @Success      200 {file} file
+// @Success      206 {file} file
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      416 {string} string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/attachments/{attachment_id} [get]
+func (h *Handler) downloadAttachment(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	attachmentID, ok := parseIDParam(c, "attachment_id")
+	if !ok {
+		return
+	}
+	attachment, content, err := h.AttachmentUseCase.Open(c.Request.Context(), attachmentID, taskID, userID)
+	if err != nil {
+		writeTaskError(c, err, "failed to download attachment")
+		return
+	}
+	defer content.Close()
+
+	c.Header("Content-Type", attachment.ContentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(c.Writer, c.Request, attachment.Filename, attachment.CreatedAt, content)
+}
+
+// @Summary      Удалить вложение
+// @Security     BearerAuth
+// @Tags         attachments
+// @Param        id             path int true "Task ID"
+// @Param        attachment_id  path int true "Attachment ID"
+// @Success      204
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/attachments/{attachment_id} [delete]
+func (h *Handler) deleteAttachment(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	attachmentID, ok := parseIDParam(c, "attachment_id")
+	if !ok {
+		return
+	}
+	if err := h.AttachmentUseCase.DeleteAttachment(c.Request.Context(), attachmentID, taskID, userID); err != nil {
+		writeTaskError(c, err, "failed to delete attachment")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"app/internal/usecase"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary      Выйти
+// @Description  Завершает текущую сессию: отзываются текущий токен доступа, её refresh-токен и остальные выданные в ней JWT.
+// @Security     BearerAuth
+// @Tags         auth
+// @Success      204
+// @Failure      401 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /auth/logout [post]
+func (h *Handler) logout(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	if err := h.UserUseCase.Logout(c.Request.Context(), claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to logout"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary      Выйти на всех устройствах
+// @Description  Завершает все сессии пользователя, включая текущую: отзываются все токены доступа и refresh-токены.
+// @Security     BearerAuth
+// @Tags         auth
+// @Success      204
+// @Failure      401 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /auth/logout-all [post]
+func (h *Handler) logoutAll(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	if err := h.UserUseCase.LogoutAll(c.Request.Context(), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to logout"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary      Мои сессии
+// @Description  Устройства, на которых выполнен вход: User-Agent, IP, время входа и последней активности (с точностью до минуты). Текущая сессия помечена current.
+// @Security     BearerAuth
+// @Tags         auth
+// @Produce      json
+// @Success      200 {object} SessionsResponse
+// @Failure      401 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /auth/sessions [get]
+func (h *Handler) getSessions(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	sessions, err := h.UserUseCase.ListSessions(c.Request.Context(), claims.UserID, claims.SessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list sessions"})
+		return
+	}
+	c.JSON(http.StatusOK, SessionsResponse{Sessions: sessions})
+}
+
+// @Summary      Завершить сессию
+// @Description  Отзывает refresh-токен сессии и все выданные в ней JWT.
+// @Security     BearerAuth
+// @Tags         auth
+// @Param        id   path int true "Session ID"
+// @Success      204
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /auth/sessions/{id} [delete]
+func (h *Handler) revokeSession(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.UserUseCase.RevokeSession(c.Request.Context(), userID, id); err != nil {
+		if errors.Is(err, usecase.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke session"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary      Подтвердить почту
+// @Description  Подтверждает почту по одноразовому токену из письма.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body VerifyEmailRequest true "payload"
+// @Success      204
+// @Failure      400 {object} map[string]string "токен недействителен или истёк"
+// @Failure      500 {object} map[string]string
+// @Router       /auth/verify-email [post]
+func (h *Handler) verifyEmail(c *gin.Context) {
+	var r VerifyEmailRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	if err := h.UserUseCase.VerifyEmail(c.Request.Context(), r.Token); err != nil {
+		if errors.Is(err, usecase.ErrVerifyTokenInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify email"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary      Отправить письмо для подтверждения ещё раз
+// @Description  Прежняя ссылка перестаёт действовать. Повторное письмо — не чаще раза в минуту.
+// @Security     BearerAuth
+// @Tags         auth
+// @Success      202
+// @Failure      401 {object} map[string]string
+// @Failure      409 {object} map[string]string "почта уже подтверждена"
+// @Failure      500 {object} map[string]string
+// @Router       /auth/verify-email/resend [post]
+func (h *Handler) resendVerification(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	if err := h.UserUseCase.ResendVerification(c.Request.Context(), userID); err != nil {
+		if errors.Is(err, usecase.ErrEmailAlreadyVerified) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send verification email"})
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
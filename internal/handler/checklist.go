@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary      Чек-лист задачи
+// @Security     BearerAuth
+// @Tags         checklist
+// @Produce      json
+// @Param        id   path int true "Task ID"
+// @Success      200 {object} ChecklistResponse
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/checklist [get]
+func (h *Handler) getChecklist(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	items, err := h.ChecklistUseCase.ListItems(c.Request.Context(), taskID, userID)
+	if err != nil {
+		writeTaskError(c, err, "failed to get checklist")
+		return
+	}
+	c.JSON(http.StatusOK, ChecklistResponse{Items: items})
+}
+
+// @Summary      Добавить пункт чек-листа
+// @Security     BearerAuth
+// @Tags         checklist
+// @Accept       json
+// @Produce      json
+// @Param        id   path int true "Task ID"
+// @Param        request body ChecklistItemRequest true "payload"
+// @Success      200 {object} entity.ChecklistItem
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/checklist [post]
+func (h *Handler) addChecklistItem(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var r ChecklistItemRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	item, err := h.ChecklistUseCase.AddItem(c.Request.Context(), taskID, userID, r.Text, r.Position)
+	if err != nil {
+		writeTaskError(c, err, "failed to add checklist item")
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// @Summary      Изменить пункт чек-листа
+// @Description  Отметить/снять отметку (done) и/или поменять текст
+// @Security     BearerAuth
+// @Tags         checklist
+// @Accept       json
+// @Produce      json
+// @Param        id       path int true "Task ID"
+// @Param        item_id  path int true "Item ID"
+// @Param        request body UpdateChecklistItemRequest true "payload"
+// @Success      200 {object} entity.ChecklistItem
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/checklist/{item_id} [patch]
+func (h *Handler) updateChecklistItem(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	itemID, ok := parseIDParam(c, "item_id")
+	if !ok {
+		return
+	}
+	var r UpdateChecklistItemRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	item, err := h.ChecklistUseCase.UpdateItem(c.Request.Context(), itemID, taskID, userID, r.Text, r.Done)
+	if err != nil {
+		writeTaskError(c, err, "failed to update checklist item")
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// @Summary      Переставить пункты чек-листа
+// @Description  item_ids — все пункты в новом порядке. Если набор пунктов успел измениться, возвращается 409.
+// @Security     BearerAuth
+// @Tags         checklist
+// @Accept       json
+// @Produce      json
+// @Param        id   path int true "Task ID"
+// @Param        request body ReorderChecklistRequest true "payload"
+// @Success      200 {object} ChecklistResponse
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/checklist/order [put]
+func (h *Handler) reorderChecklist(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var r ReorderChecklistRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	items, err := h.ChecklistUseCase.Reorder(c.Request.Context(), taskID, userID, r.ItemIDs)
+	if err != nil {
+		writeTaskError(c, err, "failed to reorder checklist")
+		return
+	}
+	c.JSON(http.StatusOK, ChecklistResponse{Items: items})
+}
+
+// @Summary      Удалить пункт чек-листа
+// @Security     BearerAuth
+// @Tags         checklist
+// @Param        id       path int true "Task ID"
+// @Param        item_id  path int true "Item ID"
+// @Success      204
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/checklist/{item_id} [delete]
+func (h *Handler) deleteChecklistItem(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	itemID, ok := parseIDParam(c, "item_id")
+	if !ok {
+		return
+	}
+	if err := h.ChecklistUseCase.DeleteItem(c.Request.Context(), itemID, taskID, userID); err != nil {
+		writeTaskError(c, err, "failed to delete checklist item")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary      Комментарии задачи
+// @Description  В хронологическом порядке; body — исходный Markdown, body_html — безопасный HTML
+// @Security     BearerAuth
+// @Tags         comments
+// @Produce      json
+// @Param        id   path int true "Task ID"
+// @Success      200 {object} CommentsResponse
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/comments [get]
+func (h *Handler) getComments(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	comments, err := h.CommentUseCase.ListComments(c.Request.Context(), taskID, userID)
+	if err != nil {
+		writeTaskError(c, err, "failed to get comments")
+		return
+	}
+	c.JSON(http.StatusOK, CommentsResponse{Comments: comments})
+}
+
+// @Summary      Написать комментарий
+// @Security     BearerAuth
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id   path int true "Task ID"
+// @Param        request body CommentRequest true "payload"
+// @Success      200 {object} entity.Comment
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/comments [post]
+func (h *Handler) createComment(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var r CommentRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	comment, err := h.CommentUseCase.CreateComment(c.Request.Context(), taskID, userID, r.Body)
+	if err != nil {
+		writeTaskError(c, err, "failed to create comment")
+		return
+	}
+	c.JSON(http.StatusOK, comment)
+}
+
+// @Summary      Изменить комментарий
+// @Description  Редактировать можно только свои комментарии
+// @Security     BearerAuth
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id          path int true "Task ID"
+// @Param        comment_id  path int true "Comment ID"
+// @Param        request body CommentRequest true "payload"
+// @Success      200 {object} entity.Comment
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/comments/{comment_id} [put]
+func (h *Handler) updateComment(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	commentID, ok := parseIDParam(c, "comment_id")
+	if !ok {
+		return
+	}
+	var r CommentRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	comment, err := h.CommentUseCase.UpdateComment(c.Request.Context(), commentID, taskID, userID, r.Body)
+	if err != nil {
+		writeTaskError(c, err, "failed to update comment")
+		return
+	}
+	c.JSON(http.StatusOK, comment)
+}
+
+// @Summary      Удалить комментарий
+// @Description  Автор удаляет свой комментарий, владелец задачи — любой
+// @Security     BearerAuth
+// @Tags         comments
+// @Param        id          path int true "Task ID"
+// @Param        comment_id  path int true "Comment ID"
+// @Success      204
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/comments/{comment_id} [delete]
+func (h *Handler) deleteComment(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	commentID, ok := parseIDParam(c, "comment_id")
+	if !ok {
+		return
+	}
+	if err := h.CommentUseCase.DeleteComment(c.Request.Context(), commentID, taskID, userID); err != nil {
+		writeTaskError(c, err, "failed to delete comment")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary      Добавить зависимость
+// @Description  Задача id не может начаться или завершиться, пока depends_on_id не выполнена. Связь, замыкающая цикл, отклоняется. Возвращает граф зависимостей задачи.
+// @Security     BearerAuth
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        id   path int true "Task ID"
+// @Param        request body AddDependencyRequest true "payload"
+// @Success      200 {object} entity.DependencyGraph
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/dependencies [post]
+func (h *Handler) addDependency(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var r AddDependencyRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	graph, err := h.TaskUseCase.AddDependency(c.Request.Context(), taskID, r.DependsOnID, userID)
+	if err != nil {
+		writeTaskError(c, err, "failed to add dependency")
+		return
+	}
+	c.JSON(http.StatusOK, graph)
+}
+
+// @Summary      Удалить зависимость
+// @Security     BearerAuth
+// @Tags         tasks
+// @Produce      json
+// @Param        id             path int true "Task ID"
+// @Param        depends_on_id  path int true "ID блокирующей задачи"
+// @Success      200 {object} entity.DependencyGraph
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/dependencies/{depends_on_id} [delete]
+func (h *Handler) removeDependency(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	dependsOnID, ok := parseIDParam(c, "depends_on_id")
+	if !ok {
+		return
+	}
+	graph, err := h.TaskUseCase.RemoveDependency(c.Request.Context(), taskID, dependsOnID, userID)
+	if err != nil {
+		writeTaskError(c, err, "failed to remove dependency")
+		return
+	}
+	c.JSON(http.StatusOK, graph)
+}
+
+// @Summary      Граф зависимостей
+// @Description  Все задачи выше (upstream — блокирующие) и ниже (downstream — заблокированные) по цепочке зависимостей, рёбра между ними и топологический порядок выполнения
+// @Security     BearerAuth
+// @Tags         tasks
+// @Produce      json
+// @Param        id   path int true "Task ID"
+// @Success      200 {object} entity.DependencyGraph
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/dependency-graph [get]
+func (h *Handler) getDependencyGraph(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	graph, err := h.TaskUseCase.DependencyGraph(c.Request.Context(), taskID, userID)
+	if err != nil {
+		writeTaskError(c, err, "failed to get dependency graph")
+		return
+	}
+	c.JSON(http.StatusOK, graph)
+}
@@ -1,6 +1,9 @@
 package handler
 
-import "app/internal/entity"
+import (
+	"app/internal/entity"
+	"time"
+)
 
 // RegisterRequest ...
 type RegisterRequest struct {
@@ -17,17 +20,226 @@ type LoginRequest struct {
 
 // CreateTaskRequest ...
 type CreateTaskRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	ProjectID   *int64          `json:"project_id"`
+	ParentID    *int64          `json:"parent_id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Priority    entity.Priority `json:"priority" swaggertype:"string" enums:"low,medium,high,urgent"`
+	StartAt     *time.Time      `json:"start_at"`
+	DueAt       *time.Time      `json:"due_at"`
+	Recurrence  string          `json:"recurrence" example:"FREQ=WEEKLY;BYDAY=MO"`
 }
 
 // UpdateTaskRequest ...
 type UpdateTaskRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Status      bool   `json:"status"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Status      entity.TaskStatus `json:"status"`
+	Priority    entity.Priority   `json:"priority" swaggertype:"string" enums:"low,medium,high,urgent"`
+	StartAt     *time.Time        `json:"start_at"`
+	DueAt       *time.Time        `json:"due_at"`
+}
+
+// RecurrenceRequest ...
+type RecurrenceRequest struct {
+	Rule string `json:"rule" example:"FREQ=MONTHLY;BYDAY=-1FR"`
+}
+
+// OccurrencesResponse ...
+type OccurrencesResponse struct {
+	Occurrences []time.Time `json:"occurrences"`
+}
+
+// TransitionRequest ...
+type TransitionRequest struct {
+	To entity.TaskStatus `json:"to"`
+}
+
+// CreateStateRequest ...
+type CreateStateRequest struct {
+	Key      entity.TaskStatus     `json:"key"`
+	Name     string                `json:"name"`
+	Category entity.StatusCategory `json:"category"`
+}
+
+// WorkflowTransitionRequest ...
+type WorkflowTransitionRequest struct {
+	From entity.TaskStatus `json:"from"`
+	To   entity.TaskStatus `json:"to"`
 }
 
 type TasksResponse struct {
-	Tasks []*entity.Task `json:"tasks"`
+	Tasks      []*entity.Task `json:"tasks"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	Total      *int64         `json:"total,omitempty"`
+}
+
+type SearchResponse struct {
+	Results []*entity.TaskSearchHit `json:"results"`
+}
+
+// LabelRequest ...
+type LabelRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color" example:"#ff8800"`
+}
+
+// AttachLabelsRequest ...
+type AttachLabelsRequest struct {
+	LabelIDs []int64 `json:"label_ids"`
+}
+
+type LabelsResponse struct {
+	Labels []*entity.Label `json:"labels"`
+}
+
+// ProjectRequest ...
+type ProjectRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// MoveTaskRequest ...
+type MoveTaskRequest struct {
+	ProjectID *int64 `json:"project_id"`
+}
+
+type ProjectsResponse struct {
+	Projects []*entity.Project `json:"projects"`
+}
+
+// SetParentRequest ...
+type SetParentRequest struct {
+	ParentID *int64 `json:"parent_id"`
+}
+
+// AddDependencyRequest ...
+type AddDependencyRequest struct {
+	DependsOnID int64 `json:"depends_on_id" binding:"required"`
+}
+
+// CreateReminderRequest ... Указывается ровно одно из полей.
+type CreateReminderRequest struct {
+	OffsetMinutes *int       `json:"offset_minutes" example:"30"`
+	RemindAt      *time.Time `json:"remind_at"`
+}
+
+type RemindersResponse struct {
+	Reminders []*entity.Reminder `json:"reminders"`
+}
+
+// CommentRequest ... Текст в Markdown.
+type CommentRequest struct {
+	Body string `json:"body" example:"Готово, см. **PR #42**"`
+}
+
+type CommentsResponse struct {
+	Comments []*entity.Comment `json:"comments"`
+}
+
+type AttachmentsResponse struct {
+	Attachments []*entity.Attachment `json:"attachments"`
+}
+
+// ChecklistItemRequest ... position (с нуля) — куда вставить; без неё пункт добавится в конец.
+type ChecklistItemRequest struct {
+	Text     string `json:"text"`
+	Position *int   `json:"position"`
+}
+
+// UpdateChecklistItemRequest ... Отсутствующие поля не меняются.
+type UpdateChecklistItemRequest struct {
+	Text *string `json:"text"`
+	Done *bool   `json:"done"`
+}
+
+// ReorderChecklistRequest ... Все пункты чек-листа в новом порядке.
+type ReorderChecklistRequest struct {
+	ItemIDs []int64 `json:"item_ids"`
+}
+
+type ChecklistResponse struct {
+	Items []*entity.ChecklistItem `json:"items"`
+}
+
+// ShareRequest ... Выдаёт доступ пользователю с этой почтой или меняет его роль.
+type ShareRequest struct {
+	Email string      `json:"email" example:"colleague@example.com"`
+	Role  entity.Role `json:"role" enums:"viewer,editor,owner"`
+}
+
+type SharesResponse struct {
+	Shares []*entity.Share `json:"shares"`
+}
+
+// SetAssigneeRequest ... assignee_id: null снимает назначение.
+type SetAssigneeRequest struct {
+	AssigneeID *int64 `json:"assignee_id"`
+}
+
+// CreateWorkspaceRequest ... Создаёт пространство; создатель становится администратором.
+type CreateWorkspaceRequest struct {
+	Name string `json:"name" example:"Команда разработки"`
+}
+
+type WorkspacesResponse struct {
+	Workspaces []*entity.Workspace `json:"workspaces"`
+}
+
+type MembersResponse struct {
+	Members []*entity.Membership `json:"members"`
+}
+
+// SetMemberRoleRequest ... Новая роль участника пространства.
+type SetMemberRoleRequest struct {
+	Role entity.WorkspaceRole `json:"role" enums:"admin,member,guest" example:"member"`
+}
+
+// InviteRequest ... Приглашение в пространство; роль по умолчанию — member.
+type InviteRequest struct {
+	Email string               `json:"email" example:"colleague@example.com"`
+	Role  entity.WorkspaceRole `json:"role" enums:"admin,member,guest" example:"member"`
+}
+
+// AcceptInvitationRequest ... Токен из приглашения.
+type AcceptInvitationRequest struct {
+	Token string `json:"token"`
+}
+
+type RolesResponse struct {
+	Roles []*entity.AccountRole `json:"roles"`
+}
+
+type RoleGrantsResponse struct {
+	Roles []*entity.RoleGrant `json:"roles"`
+}
+
+// RefreshRequest ...
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// VerifyEmailRequest ...
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// ForgotPasswordRequest ...
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+// ResetPasswordRequest ... Токен сброса и новый пароль (не короче 8 символов).
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type SessionsResponse struct {
+	Sessions []*entity.Session `json:"sessions"`
+}
+
+type UsersResponse struct {
+	Users []*entity.User `json:"users"`
+	Total int64          `json:"total"`
 }
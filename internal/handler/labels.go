@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"app/internal/entity"
+	"app/internal/usecase"
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// writeLabelError переводит ошибку LabelUseCase в HTTP-ответ.
+func writeLabelError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "label not found"})
+	case errors.Is(err, usecase.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrLabelExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// @Summary      Метки пространства
+// @Description  Метки общие для всех участников текущего рабочего пространства (X-Workspace-ID)
+// @Security     BearerAuth
+// @Tags         labels
+// @Produce      json
+// @Success      200 {object} LabelsResponse
+// @Failure      401 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /labels [get]
+func (h *Handler) getLabels(c *gin.Context) {
+	labels, err := h.LabelUseCase.ListLabels(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list labels"})
+		return
+	}
+	c.JSON(http.StatusOK, LabelsResponse{Labels: labels})
+}
+
+// @Summary      Создать метку
+// @Security     BearerAuth
+// @Tags         labels
+// @Accept       json
+// @Produce      json
+// @Param        request body LabelRequest true "payload"
+// @Success      201 {object} entity.Label
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /labels [post]
+func (h *Handler) createLabel(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	var r LabelRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	label, err := h.LabelUseCase.CreateLabel(c.Request.Context(), &entity.Label{
+		OwnerID: userID,
+		Name:    r.Name,
+		Color:   r.Color,
+	})
+	if err != nil {
+		writeLabelError(c, err, "failed to create label")
+		return
+	}
+	c.JSON(http.StatusCreated, label)
+}
+
+// @Summary      Одна метка
+// @Security     BearerAuth
+// @Tags         labels
+// @Produce      json
+// @Param        id   path int true "Label ID"
+// @Success      200 {object} entity.Label
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /labels/{id} [get]
+func (h *Handler) getLabel(c *gin.Context) {
+	labelID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	label, err := h.LabelUseCase.GetLabel(c.Request.Context(), labelID)
+	if err != nil {
+		writeLabelError(c, err, "failed to get label")
+		return
+	}
+	c.JSON(http.StatusOK, label)
+}
+
+// @Summary      Изменить метку
+// @Security     BearerAuth
+// @Tags         labels
+// @Accept       json
+// @Produce      json
+// @Param        id   path int true "Label ID"
+// @Param        request body LabelRequest true "payload"
+// @Success      200 {object} entity.Label
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /labels/{id} [put]
+func (h *Handler) updateLabel(c *gin.Context) {
+	labelID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var r LabelRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	label, err := h.LabelUseCase.UpdateLabel(c.Request.Context(), &entity.Label{
+		ID:    labelID,
+		Name:  r.Name,
+		Color: r.Color,
+	})
+	if err != nil {
+		writeLabelError(c, err, "failed to update label")
+		return
+	}
+	c.JSON(http.StatusOK, label)
+}
+
+// @Summary      Удалить метку
+// @Description  Метка снимается со всех задач
+// @Security     BearerAuth
+// @Tags         labels
+// @Param        id   path int true "Label ID"
+// @Success      204  "no content"
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /labels/{id} [delete]
+func (h *Handler) deleteLabel(c *gin.Context) {
+	labelID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.LabelUseCase.DeleteLabel(c.Request.Context(), labelID); err != nil {
+		writeLabelError(c, err, "failed to delete label")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary      Повесить метки на задачу
+// @Security     BearerAuth
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        id   path int true "Task ID"
+// @Param        request body AttachLabelsRequest true "payload"
+// @Success      200 {object} entity.Task
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/labels [post]
+func (h *Handler) attachLabels(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var r AttachLabelsRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	task, err := h.LabelUseCase.AttachLabels(c.Request.Context(), taskID, userID, r.LabelIDs)
+	if err != nil {
+		writeTaskError(c, err, "failed to attach labels")
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+// @Summary      Снять метку с задачи
+// @Security     BearerAuth
+// @Tags         tasks
+// @Produce      json
+// @Param        id        path int true "Task ID"
+// @Param        label_id  path int true "Label ID"
+// @Success      200 {object} entity.Task
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/labels/{label_id} [delete]
+func (h *Handler) detachLabel(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	labelID, ok := parseIDParam(c, "label_id")
+	if !ok {
+		return
+	}
+	task, err := h.LabelUseCase.DetachLabel(c.Request.Context(), taskID, userID, labelID)
+	if err != nil {
+		writeTaskError(c, err, "failed to detach label")
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
@@ -1,14 +1,26 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"app/internal/entity"
 	"app/internal/security"
+	"app/internal/tenant"
+	"app/internal/usecase"
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// WorkspaceHeader выбирает рабочее пространство запроса; без него запрос
+// идёт в личное пространство пользователя.
+const WorkspaceHeader = "X-Workspace-ID"
+
+// AuthMiddleware проверяет JWT (подпись, срок, отзыв), что аккаунт не
+// отключён, загружает текущие системные роли пользователя и определяет
+// рабочее пространство запроса.
+func AuthMiddleware(users *usecase.UserUseCase, rbac *usecase.RBACUseCase, workspaces *usecase.WorkspaceUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
@@ -23,8 +35,90 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// сохраняем user_id в контекст, чтобы хендлеры знали, кто вызывает
+		user, err := users.Authenticate(c.Request.Context(), claims)
+		if err != nil {
+			if errors.Is(err, usecase.ErrAccountDisabled) || errors.Is(err, usecase.ErrPasswordResetRequired) ||
+				errors.Is(err, usecase.ErrTokenRevoked) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to check account"})
+			return
+		}
+
+		// роли берутся из базы, а не из claims: иначе разжалованный
+		// администратор сохранял бы права до истечения токена
+		roles, err := rbac.RoleNames(c.Request.Context(), claims.UserID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to load roles"})
+			return
+		}
+
+		var workspaceID *int64
+		if v := c.GetHeader(WorkspaceHeader); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid " + WorkspaceHeader})
+				return
+			}
+			workspaceID = &id
+		}
+		membership, err := workspaces.Resolve(c.Request.Context(), claims.UserID, workspaceID)
+		if err != nil {
+			if errors.Is(err, usecase.ErrForbidden) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve workspace"})
+			return
+		}
+
+		// сохраняем user_id в контекст, чтобы хендлеры знали, кто вызывает,
+		// а членство — в контекст запроса: по нему репозитории ограничивают данные
 		c.Set("user_id", claims.UserID)
+		c.Set("claims", claims)
+		c.Set("user", user)
+		c.Set("roles", roles)
+		c.Set("workspace_id", membership.WorkspaceID)
+		c.Request = c.Request.WithContext(tenant.With(c.Request.Context(), *membership))
+		c.Next()
+	}
+}
+
+// RequireVerifiedEmail не даёт пользователю с неподтверждённой почтой
+// создавать задачи, если сервис так настроен. Ставится после AuthMiddleware.
+func (h *Handler) RequireVerifiedEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		v, _ := c.Get("user")
+		user, ok := v.(*entity.User)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+			return
+		}
+		if err := h.UserUseCase.CheckCanCreateTasks(user); err != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequirePermission пропускает запрос, только если одна из системных ролей
+// пользователя (загруженных AuthMiddleware) даёт разрешение permission.
+// Ставится после AuthMiddleware.
+func (h *Handler) RequirePermission(permission entity.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roles, _ := c.Get("roles")
+		names, _ := roles.([]string)
+		ok, err := h.RBACUseCase.Allowed(c.Request.Context(), names, permission)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to check permission"})
+			return
+		}
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "missing permission " + string(permission)})
+			return
+		}
 		c.Next()
 	}
 }
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"app/internal/entity"
+	"app/internal/usecase"
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// writeProjectError переводит ошибку ProjectUseCase в HTTP-ответ.
+func writeProjectError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "project not found"})
+	case errors.Is(err, usecase.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrUserNotFound), errors.Is(err, usecase.ErrShareNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// @Summary      Мои проекты
+// @Description  С scope=shared — чужие проекты, к которым мне выдан доступ
+// @Security     BearerAuth
+// @Tags         projects
+// @Produce      json
+// @Param        scope query string false "own (по умолчанию), shared или all" Enums(own, shared, all)
+// @Success      200 {object} ProjectsResponse
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /projects [get]
+func (h *Handler) getProjects(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	scope, ok := parseScope(c)
+	if !ok {
+		return
+	}
+	projects, err := h.ProjectUseCase.ListProjects(c.Request.Context(), userID, scope)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list projects"})
+		return
+	}
+	c.JSON(http.StatusOK, ProjectsResponse{Projects: projects})
+}
+
+// @Summary      Создать проект
+// @Security     BearerAuth
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        request body ProjectRequest true "payload"
+// @Success      201 {object} entity.Project
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /projects [post]
+func (h *Handler) createProject(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	var r ProjectRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	project, err := h.ProjectUseCase.CreateProject(c.Request.Context(), &entity.Project{
+		OwnerID:     userID,
+		Name:        r.Name,
+		Description: r.Description,
+	})
+	if err != nil {
+		writeProjectError(c, err, "failed to create project")
+		return
+	}
+	c.JSON(http.StatusCreated, project)
+}
+
+// @Summary      Один проект
+// @Security     BearerAuth
+// @Tags         projects
+// @Produce      json
+// @Param        id   path int true "Project ID"
+// @Success      200 {object} entity.Project
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /projects/{id} [get]
+func (h *Handler) getProject(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	projectID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	project, err := h.ProjectUseCase.GetProject(c.Request.Context(), projectID, userID)
+	if err != nil {
+		writeProjectError(c, err, "failed to get project")
+		return
+	}
+	c.JSON(http.StatusOK, project)
+}
+
+// @Summary      Изменить проект
+// @Security     BearerAuth
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id   path int true "Project ID"
+// @Param        request body ProjectRequest true "payload"
+// @Success      200 {object} entity.Project
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /projects/{id} [put]
+func (h *Handler) updateProject(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	projectID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var r ProjectRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	project, err := h.ProjectUseCase.UpdateProject(c.Request.Context(), userID, &entity.Project{
+		ID:          projectID,
+		Name:        r.Name,
+		Description: r.Description,
+	})
+	if err != nil {
+		writeProjectError(c, err, "failed to update project")
+		return
+	}
+	c.JSON(http.StatusOK, project)
+}
+
+// @Summary      Удалить проект
+// @Description  Задачи проекта не удаляются, а остаются без проекта
+// @Security     BearerAuth
+// @Tags         projects
+// @Param        id   path int true "Project ID"
+// @Success      204  "no content"
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /projects/{id} [delete]
+func (h *Handler) deleteProject(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	projectID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.ProjectUseCase.DeleteProject(c.Request.Context(), projectID, userID); err != nil {
+		writeProjectError(c, err, "failed to delete project")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary      Задачи проекта
+// @Description  Принимает те же параметры фильтрации, сортировки и пагинации, что и GET /tasks
+// @Security     BearerAuth
+// @Tags         projects
+// @Produce      json
+// @Param        id          path  int    true  "Project ID"
+// @Param        filter      query string false "выражение фильтра"
+// @Param        sort        query string false "ключи сортировки"
+// @Param        limit       query int    false "размер страницы"
+// @Param        cursor      query string false "next_cursor из предыдущего ответа"
+// @Success      200 {object} TasksResponse
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /projects/{id}/tasks [get]
+func (h *Handler) getProjectTasks(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	projectID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	project, err := h.ProjectUseCase.GetProject(c.Request.Context(), projectID, userID)
+	if err != nil {
+		writeProjectError(c, err, "failed to get project")
+		return
+	}
+	// задачи проекта принадлежат его владельцу, в том числе в чужом проекте
+	h.listTasks(c, project.OwnerID, &projectID)
+}
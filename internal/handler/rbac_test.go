@@ -0,0 +1,313 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"app/internal/entity"
+	"app/internal/security"
+	"app/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+// permissionRoutes — ожидаемое разрешение для каждого маршрута с
+// RequirePermission. Таблица ведётся отдельно от router.go: новый маршрут
+// без записи здесь роняет TestEveryProtectedRouteRequiresPermission.
+var permissionRoutes = []struct {
+	method     string
+	path       string
+	permission entity.Permission
+}{
+	{http.MethodPost, "/tasks", entity.PermTasksWrite},
+	{http.MethodGet, "/tasks", entity.PermTasksRead},
+	{http.MethodGet, "/tasks/search", entity.PermTasksRead},
+	{http.MethodGet, "/tasks/:id", entity.PermTasksRead},
+	{http.MethodPut, "/tasks/:id", entity.PermTasksWrite},
+	{http.MethodPatch, "/tasks/:id/complete", entity.PermTasksWrite},
+	{http.MethodPost, "/tasks/:id/transitions", entity.PermTasksWrite},
+	{http.MethodDelete, "/tasks/:id", entity.PermTasksDelete},
+	{http.MethodGet, "/tasks/:id/subtasks", entity.PermTasksRead},
+	{http.MethodPut, "/tasks/:id/parent", entity.PermTasksWrite},
+	{http.MethodPut, "/tasks/:id/project", entity.PermTasksWrite},
+	{http.MethodPut, "/tasks/:id/assignee", entity.PermTasksWrite},
+	{http.MethodPost, "/tasks/:id/labels", entity.PermTasksWrite},
+	{http.MethodDelete, "/tasks/:id/labels/:label_id", entity.PermTasksWrite},
+	{http.MethodPost, "/tasks/:id/dependencies", entity.PermTasksWrite},
+	{http.MethodDelete, "/tasks/:id/dependencies/:depends_on_id", entity.PermTasksWrite},
+	{http.MethodGet, "/tasks/:id/dependency-graph", entity.PermTasksRead},
+	{http.MethodPut, "/tasks/:id/recurrence", entity.PermTasksWrite},
+	{http.MethodGet, "/tasks/:id/occurrences", entity.PermTasksRead},
+	{http.MethodGet, "/tasks/:id/reminders", entity.PermTasksRead},
+	{http.MethodPost, "/tasks/:id/reminders", entity.PermTasksWrite},
+	{http.MethodDelete, "/tasks/:id/reminders/:reminder_id", entity.PermTasksWrite},
+	{http.MethodGet, "/tasks/:id/comments", entity.PermTasksRead},
+	{http.MethodPost, "/tasks/:id/comments", entity.PermTasksWrite},
+	{http.MethodPut, "/tasks/:id/comments/:comment_id", entity.PermTasksWrite},
+	{http.MethodDelete, "/tasks/:id/comments/:comment_id", entity.PermTasksWrite},
+	{http.MethodGet, "/tasks/:id/attachments", entity.PermTasksRead},
+	{http.MethodPost, "/tasks/:id/attachments", entity.PermTasksWrite},
+	{http.MethodGet, "/tasks/:id/attachments/:attachment_id", entity.PermTasksRead},
+	{http.MethodDelete, "/tasks/:id/attachments/:attachment_id", entity.PermTasksWrite},
+	{http.MethodGet, "/tasks/:id/checklist", entity.PermTasksRead},
+	{http.MethodPost, "/tasks/:id/checklist", entity.PermTasksWrite},
+	{http.MethodPut, "/tasks/:id/checklist/order", entity.PermTasksWrite},
+	{http.MethodPatch, "/tasks/:id/checklist/:item_id", entity.PermTasksWrite},
+	{http.MethodDelete, "/tasks/:id/checklist/:item_id", entity.PermTasksWrite},
+	{http.MethodGet, "/tasks/:id/shares", entity.PermTasksRead},
+	{http.MethodPut, "/tasks/:id/shares", entity.PermTasksWrite},
+	{http.MethodDelete, "/tasks/:id/shares/:user_id", entity.PermTasksWrite},
+
+	{http.MethodGet, "/projects", entity.PermProjectsRead},
+	{http.MethodPost, "/projects", entity.PermProjectsWrite},
+	{http.MethodGet, "/projects/:id", entity.PermProjectsRead},
+	{http.MethodPut, "/projects/:id", entity.PermProjectsWrite},
+	{http.MethodDelete, "/projects/:id", entity.PermProjectsDelete},
+	{http.MethodGet, "/projects/:id/tasks", entity.PermProjectsRead},
+	{http.MethodGet, "/projects/:id/shares", entity.PermProjectsRead},
+	{http.MethodPut, "/projects/:id/shares", entity.PermProjectsWrite},
+	{http.MethodDelete, "/projects/:id/shares/:user_id", entity.PermProjectsWrite},
+
+	{http.MethodGet, "/labels", entity.PermLabelsRead},
+	{http.MethodPost, "/labels", entity.PermLabelsWrite},
+	{http.MethodGet, "/labels/:id", entity.PermLabelsRead},
+	{http.MethodPut, "/labels/:id", entity.PermLabelsWrite},
+	{http.MethodDelete, "/labels/:id", entity.PermLabelsWrite},
+
+	{http.MethodGet, "/workflow", entity.PermWorkflowRead},
+	{http.MethodPost, "/workflow/states", entity.PermWorkflowWrite},
+	{http.MethodDelete, "/workflow/states/:key", entity.PermWorkflowWrite},
+	{http.MethodPost, "/workflow/transitions", entity.PermWorkflowWrite},
+	{http.MethodDelete, "/workflow/transitions", entity.PermWorkflowWrite},
+
+	{http.MethodGet, "/workspaces", entity.PermWorkspacesRead},
+	{http.MethodPost, "/workspaces", entity.PermWorkspacesWrite},
+	{http.MethodGet, "/workspaces/:id/members", entity.PermWorkspacesRead},
+	{http.MethodPut, "/workspaces/:id/members/:user_id", entity.PermWorkspacesWrite},
+	{http.MethodDelete, "/workspaces/:id/members/:user_id", entity.PermWorkspacesWrite},
+	{http.MethodPost, "/workspaces/:id/invitations", entity.PermWorkspacesWrite},
+
+	{http.MethodPost, "/invitations/accept", entity.PermWorkspacesWrite},
+
+	{http.MethodGet, "/admin/users", entity.PermUsersManage},
+	{http.MethodGet, "/admin/users/:id", entity.PermUsersManage},
+	{http.MethodGet, "/admin/users/:id/task-counts", entity.PermUsersManage},
+	{http.MethodPost, "/admin/users/:id/disable", entity.PermUsersManage},
+	{http.MethodPost, "/admin/users/:id/enable", entity.PermUsersManage},
+	{http.MethodPost, "/admin/users/:id/password-reset", entity.PermUsersManage},
+	{http.MethodDelete, "/admin/users/:id", entity.PermUsersManage},
+	{http.MethodGet, "/admin/roles", entity.PermRolesManage},
+	{http.MethodGet, "/admin/users/:id/roles", entity.PermRolesManage},
+	{http.MethodPut, "/admin/users/:id/roles/:role", entity.PermRolesManage},
+	{http.MethodDelete, "/admin/users/:id/roles/:role", entity.PermRolesManage},
+}
+
+// selfServiceRoutes доступны любому вошедшему пользователю без разрешений.
+var selfServiceRoutes = map[string]bool{
+	"POST /auth/verify-email/resend": true,
+	"POST /auth/logout":              true,
+	"POST /auth/logout-all":          true,
+	"GET /auth/sessions":             true,
+	"DELETE /auth/sessions/:id":      true,
+}
+
+var allPermissions = []entity.Permission{
+	entity.PermTasksRead, entity.PermTasksWrite, entity.PermTasksDelete,
+	entity.PermProjectsRead, entity.PermProjectsWrite, entity.PermProjectsDelete,
+	entity.PermLabelsRead, entity.PermLabelsWrite,
+	entity.PermWorkflowRead, entity.PermWorkflowWrite,
+	entity.PermWorkspacesRead, entity.PermWorkspacesWrite,
+	entity.PermRolesManage, entity.PermUsersManage,
+}
+
+// fakeUsers отдаёт единственного активного пользователя.
+type fakeUsers struct {
+	usecase.RepoUser
+}
+
+func (fakeUsers) GetByID(_ context.Context, id int64) (*entity.User, error) {
+	if id != testUserID {
+		return nil, sql.ErrNoRows
+	}
+	return &entity.User{ID: id, Email: "user@example.com"}, nil
+}
+
+// fakeRBAC: для каждого разрешения p есть роль "only:p" с одним этим
+// разрешением и роль "without:p" со всеми остальными. Роли пользователя
+// задаёт тест.
+type fakeRBAC struct {
+	usecase.RepoRBAC
+
+	mu     sync.Mutex
+	grants []string
+}
+
+func (r *fakeRBAC) setRoles(roles ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.grants = roles
+}
+
+func (r *fakeRBAC) Roles(context.Context) ([]*entity.AccountRole, error) {
+	var roles []*entity.AccountRole
+	for _, p := range allPermissions {
+		var others []entity.Permission
+		for _, o := range allPermissions {
+			if o != p {
+				others = append(others, o)
+			}
+		}
+		roles = append(roles,
+			&entity.AccountRole{Name: "only:" + string(p), Permissions: []entity.Permission{p}},
+			&entity.AccountRole{Name: "without:" + string(p), Permissions: others},
+		)
+	}
+	roles = append(roles, &entity.AccountRole{Name: entity.RoleNameAdmin, Permissions: allPermissions})
+	return roles, nil
+}
+
+func (r *fakeRBAC) UserRoles(_ context.Context, userID int64) ([]*entity.RoleGrant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	grants := make([]*entity.RoleGrant, len(r.grants))
+	for i, role := range r.grants {
+		grants[i] = &entity.RoleGrant{Role: role}
+	}
+	return grants, nil
+}
+
+// fakeWorkspaces: у пользователя есть только личное пространство.
+type fakeWorkspaces struct {
+	usecase.RepoWorkspace
+}
+
+func (fakeWorkspaces) Personal(_ context.Context, userID int64) (*entity.Membership, error) {
+	return &entity.Membership{WorkspaceID: 1, UserID: userID, Role: entity.WorkspaceAdmin}, nil
+}
+
+const testUserID = 1
+
+// newTestRouter собирает настоящий роутер. Проверка прав идёт до
+// бизнес-логики, поэтому остальные use case не нужны: хендлер, до которого
+// дошёл запрос, падает на nil и gin.Recovery отвечает 500.
+func newTestRouter(t *testing.T) (*gin.Engine, *fakeRBAC) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	gin.DefaultErrorWriter = io.Discard
+
+	rbac := &fakeRBAC{}
+	users := usecase.NewUserUseCase(fakeUsers{}, rbac, nil, nil, nil, entity.MailLinks{},
+		entity.TokenTTL{Access: time.Minute, Refresh: time.Hour}, false)
+	r, _ := NewHandler(nil, users, nil, nil, nil, nil, nil, nil, nil, nil,
+		usecase.NewWorkspaceUseCase(fakeWorkspaces{}, fakeUsers{}),
+		usecase.NewRBACUseCase(rbac, fakeUsers{}), nil)
+	return r, rbac
+}
+
+func testToken(t *testing.T, roles ...string) string {
+	t.Helper()
+	token, err := security.GenerateJWT(testUserID, "user@example.com", roles, 0, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+var routeParamRe = regexp.MustCompile(`:[a-z_]+`)
+
+func serve(r *gin.Engine, method, path, token string) *httptest.ResponseRecorder {
+	url := routeParamRe.ReplaceAllStringFunc(path, func(p string) string {
+		switch p {
+		case ":role":
+			return entity.RoleNameReader
+		case ":key":
+			return "review"
+		}
+		return "1"
+	})
+	req := httptest.NewRequest(method, url, strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func deniedFor(w *httptest.ResponseRecorder, perm entity.Permission) bool {
+	return w.Code == http.StatusForbidden && strings.Contains(w.Body.String(), "missing permission "+string(perm))
+}
+
+func TestRequirePermission(t *testing.T) {
+	r, rbac := newTestRouter(t)
+	token := testToken(t)
+
+	for _, rt := range permissionRoutes {
+		t.Run(rt.method+" "+rt.path, func(t *testing.T) {
+			rbac.setRoles("without:" + string(rt.permission))
+			if w := serve(r, rt.method, rt.path, token); !deniedFor(w, rt.permission) {
+				t.Errorf("without %s: got %d %s, want 403", rt.permission, w.Code, w.Body)
+			}
+
+			rbac.setRoles("only:" + string(rt.permission))
+			w := serve(r, rt.method, rt.path, token)
+			if w.Code == http.StatusUnauthorized || w.Code == http.StatusForbidden {
+				t.Errorf("with %s: got %d %s, want the request to reach the handler", rt.permission, w.Code, w.Body)
+			}
+		})
+	}
+}
+
+// Права определяются ролями из базы, а не из JWT: разжалованный
+// администратор теряет их сразу, а выданная роль действует без нового входа.
+func TestRequirePermissionIgnoresTokenRoles(t *testing.T) {
+	r, rbac := newTestRouter(t)
+
+	rbac.setRoles(entity.RoleNameReader)
+	if w := serve(r, http.MethodGet, "/admin/roles", testToken(t, entity.RoleNameAdmin)); !deniedFor(w, entity.PermRolesManage) {
+		t.Fatalf("stale admin token: got %d %s, want 403", w.Code, w.Body)
+	}
+
+	rbac.setRoles(entity.RoleNameAdmin)
+	if w := serve(r, http.MethodGet, "/admin/roles", testToken(t)); w.Code == http.StatusForbidden {
+		t.Fatalf("freshly granted admin: got 403 %s", w.Body)
+	}
+}
+
+func TestRequirePermissionNeedsToken(t *testing.T) {
+	r, _ := newTestRouter(t)
+	for _, rt := range permissionRoutes {
+		if w := serve(r, rt.method, rt.path, ""); w.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s without token: got %d, want 401", rt.method, rt.path, w.Code)
+		}
+	}
+}
+
+func TestEveryProtectedRouteRequiresPermission(t *testing.T) {
+	r, _ := newTestRouter(t)
+
+	listed := make(map[string]bool, len(permissionRoutes))
+	for _, rt := range permissionRoutes {
+		listed[rt.method+" "+rt.path] = true
+	}
+	public := map[string]bool{}
+	for _, p := range []string{"/auth/register", "/auth/login", "/auth/refresh", "/auth/verify-email",
+		"/auth/password/forgot", "/auth/password/reset"} {
+		public[http.MethodPost+" "+p] = true
+	}
+
+	for _, route := range r.Routes() {
+		key := route.Method + " " + route.Path
+		if !listed[key] && !public[key] && !selfServiceRoutes[key] {
+			t.Errorf("%s is not covered by permissionRoutes", key)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// @Summary      Правило повторения
+// @Description  Подмножество RRULE (RFC 5545): FREQ=DAILY|WEEKLY|MONTHLY|YEARLY, INTERVAL, BYDAY, COUNT, UNTIL. Серия начинается с текущего due_at задачи. Пустое правило снимает повторение.
+// @Security     BearerAuth
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        id   path int true "Task ID"
+// @Param        request body RecurrenceRequest true "payload"
+// @Success      200 {object} entity.Task
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/recurrence [put]
+func (h *Handler) setRecurrence(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var r RecurrenceRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	task, err := h.TaskUseCase.SetRecurrence(c.Request.Context(), taskID, userID, r.Rule)
+	if err != nil {
+		writeTaskError(c, err, "failed to set recurrence")
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+// @Summary      Ближайшие повторения
+// @Description  Сроки следующих повторений после текущего, с учётом COUNT и UNTIL
+// @Security     BearerAuth
+// @Tags         tasks
+// @Produce      json
+// @Param        id     path  int true  "Task ID"
+// @Param        count  query int false "сколько повторений (по умолчанию 5, максимум 100)"
+// @Success      200 {object} OccurrencesResponse
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/occurrences [get]
+func (h *Handler) getOccurrences(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	count, err := strconv.Atoi(c.DefaultQuery("count", "0"))
+	if err != nil || count < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid count"})
+		return
+	}
+	occurrences, err := h.TaskUseCase.PreviewOccurrences(c.Request.Context(), taskID, userID, count)
+	if err != nil {
+		writeTaskError(c, err, "failed to preview occurrences")
+		return
+	}
+	c.JSON(http.StatusOK, OccurrencesResponse{Occurrences: occurrences})
+}
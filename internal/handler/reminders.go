@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// @Summary      Добавить напоминание
+// @Description  offset_minutes — за сколько минут до due_at напомнить, remind_at — в какой момент; указывается ровно одно из двух
+// @Security     BearerAuth
+// @Tags         reminders
+// @Accept       json
+// @Produce      json
+// @Param        id   path int true "Task ID"
+// @Param        request body CreateReminderRequest true "payload"
+// @Success      200 {object} entity.Reminder
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/reminders [post]
+func (h *Handler) createReminder(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var r CreateReminderRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	reminder, err := h.ReminderUseCase.CreateReminder(c.Request.Context(), taskID, userID, r.OffsetMinutes, r.RemindAt)
+	if err != nil {
+		writeTaskError(c, err, "failed to create reminder")
+		return
+	}
+	c.JSON(http.StatusOK, reminder)
+}
+
+// @Summary      Напоминания задачи
+// @Security     BearerAuth
+// @Tags         reminders
+// @Produce      json
+// @Param        id   path int true "Task ID"
+// @Success      200 {object} RemindersResponse
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/reminders [get]
+func (h *Handler) getReminders(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	reminders, err := h.ReminderUseCase.ListReminders(c.Request.Context(), taskID, userID)
+	if err != nil {
+		writeTaskError(c, err, "failed to get reminders")
+		return
+	}
+	c.JSON(http.StatusOK, RemindersResponse{Reminders: reminders})
+}
+
+// @Summary      Удалить напоминание
+// @Security     BearerAuth
+// @Tags         reminders
+// @Param        id           path int true "Task ID"
+// @Param        reminder_id  path int true "Reminder ID"
+// @Success      204
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/reminders/{reminder_id} [delete]
+func (h *Handler) deleteReminder(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	reminderID, ok := parseIDParam(c, "reminder_id")
+	if !ok {
+		return
+	}
+	if err := h.ReminderUseCase.DeleteReminder(c.Request.Context(), reminderID, taskID, userID); err != nil {
+		writeTaskError(c, err, "failed to delete reminder")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"app/internal/usecase"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// writeRoleError переводит ошибку usecase системных ролей в HTTP-ответ.
+func writeRoleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrUserNotFound), errors.Is(err, usecase.ErrRoleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrLastSystemAdmin):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// @Summary      Системные роли
+// @Description  Роли и их разрешения. Нужно разрешение roles:manage.
+// @Security     BearerAuth
+// @Tags         admin
+// @Produce      json
+// @Success      200 {object} RolesResponse
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /admin/roles [get]
+func (h *Handler) getRoles(c *gin.Context) {
+	roles, err := h.RBACUseCase.ListRoles(c.Request.Context())
+	if err != nil {
+		writeRoleError(c, err, "failed to list roles")
+		return
+	}
+	c.JSON(http.StatusOK, RolesResponse{Roles: roles})
+}
+
+// @Summary      Роли пользователя
+// @Description  Нужно разрешение roles:manage.
+// @Security     BearerAuth
+// @Tags         admin
+// @Produce      json
+// @Param        id   path int true "User ID"
+// @Success      200 {object} RoleGrantsResponse
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /admin/users/{id}/roles [get]
+func (h *Handler) getUserRoles(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	grants, err := h.RBACUseCase.UserRoles(c.Request.Context(), userID)
+	if err != nil {
+		writeRoleError(c, err, "failed to get roles")
+		return
+	}
+	c.JSON(http.StatusOK, RoleGrantsResponse{Roles: grants})
+}
+
+// @Summary      Выдать роль
+// @Description  Роль действует сразу, со следующего запроса пользователя. Нужно разрешение roles:manage.
+// @Security     BearerAuth
+// @Tags         admin
+// @Produce      json
+// @Param        id    path int    true "User ID"
+// @Param        role  path string true "Role" example(admin)
+// @Success      200 {object} RoleGrantsResponse
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /admin/users/{id}/roles/{role} [put]
+func (h *Handler) grantRole(c *gin.Context) {
+	adminID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	grants, err := h.RBACUseCase.GrantRole(c.Request.Context(), adminID, userID, c.Param("role"))
+	if err != nil {
+		writeRoleError(c, err, "failed to grant role")
+		return
+	}
+	c.JSON(http.StatusOK, RoleGrantsResponse{Roles: grants})
+}
+
+// @Summary      Отозвать роль
+// @Description  Последнего администратора разжаловать нельзя. Изменение действует сразу. Нужно разрешение roles:manage.
+// @Security     BearerAuth
+// @Tags         admin
+// @Produce      json
+// @Param        id    path int    true "User ID"
+// @Param        role  path string true "Role"
+// @Success      200 {object} RoleGrantsResponse
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /admin/users/{id}/roles/{role} [delete]
+func (h *Handler) revokeRole(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	grants, err := h.RBACUseCase.RevokeRole(c.Request.Context(), userID, c.Param("role"))
+	if err != nil {
+		writeRoleError(c, err, "failed to revoke role")
+		return
+	}
+	c.JSON(http.StatusOK, RoleGrantsResponse{Roles: grants})
+}
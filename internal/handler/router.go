@@ -2,38 +2,189 @@ package handler
 
 import (
 	"app/internal/entity"
+	"app/internal/security"
 	"app/internal/usecase"
 	"database/sql"
 	"errors"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Handler struct {
-	TaskUseCase *usecase.TaskUseCase
-	UserUseCase *usecase.UserUseCase
+	TaskUseCase       *usecase.TaskUseCase
+	UserUseCase       *usecase.UserUseCase
+	WorkflowUseCase   *usecase.WorkflowUseCase
+	LabelUseCase      *usecase.LabelUseCase
+	ProjectUseCase    *usecase.ProjectUseCase
+	ReminderUseCase   *usecase.ReminderUseCase
+	CommentUseCase    *usecase.CommentUseCase
+	AttachmentUseCase *usecase.AttachmentUseCase
+	ChecklistUseCase  *usecase.ChecklistUseCase
+	ShareUseCase      *usecase.ShareUseCase
+	WorkspaceUseCase  *usecase.WorkspaceUseCase
+	RBACUseCase       *usecase.RBACUseCase
+	AdminUseCase      *usecase.AdminUseCase
 }
 
-func NewHandler(taskUC *usecase.TaskUseCase, userUC *usecase.UserUseCase) (*gin.Engine, *Handler) {
-	h := &Handler{TaskUseCase: taskUC, UserUseCase: userUC}
+func NewHandler(
+	taskUC *usecase.TaskUseCase,
+	userUC *usecase.UserUseCase,
+	workflowUC *usecase.WorkflowUseCase,
+	labelUC *usecase.LabelUseCase,
+	projectUC *usecase.ProjectUseCase,
+	reminderUC *usecase.ReminderUseCase,
+	commentUC *usecase.CommentUseCase,
+	attachmentUC *usecase.AttachmentUseCase,
+	checklistUC *usecase.ChecklistUseCase,
+	shareUC *usecase.ShareUseCase,
+	workspaceUC *usecase.WorkspaceUseCase,
+	rbacUC *usecase.RBACUseCase,
+	adminUC *usecase.AdminUseCase,
+) (*gin.Engine, *Handler) {
+	h := &Handler{
+		TaskUseCase:       taskUC,
+		UserUseCase:       userUC,
+		WorkflowUseCase:   workflowUC,
+		LabelUseCase:      labelUC,
+		ProjectUseCase:    projectUC,
+		ReminderUseCase:   reminderUC,
+		CommentUseCase:    commentUC,
+		AttachmentUseCase: attachmentUC,
+		ChecklistUseCase:  checklistUC,
+		ShareUseCase:      shareUC,
+		WorkspaceUseCase:  workspaceUC,
+		RBACUseCase:       rbacUC,
+		AdminUseCase:      adminUC,
+	}
 	r := gin.New()
 	r.Use(gin.Recovery())
 
 	// Публичные
 	r.POST("/auth/register", h.registerUser)
 	r.POST("/auth/login", h.login)
+	r.POST("/auth/refresh", h.refreshToken)
+	r.POST("/auth/verify-email", h.verifyEmail)
+	r.POST("/auth/password/forgot", h.forgotPassword)
+	r.POST("/auth/password/reset", h.resetPassword)
+
+	// разрешения системных ролей (RBAC) проверяются до бизнес-логики
+	var (
+		readTasks       = h.RequirePermission(entity.PermTasksRead)
+		writeTasks      = h.RequirePermission(entity.PermTasksWrite)
+		deleteTasks     = h.RequirePermission(entity.PermTasksDelete)
+		readProjects    = h.RequirePermission(entity.PermProjectsRead)
+		writeProjects   = h.RequirePermission(entity.PermProjectsWrite)
+		deleteProjects  = h.RequirePermission(entity.PermProjectsDelete)
+		readLabels      = h.RequirePermission(entity.PermLabelsRead)
+		writeLabels     = h.RequirePermission(entity.PermLabelsWrite)
+		readWorkflow    = h.RequirePermission(entity.PermWorkflowRead)
+		writeWorkflow   = h.RequirePermission(entity.PermWorkflowWrite)
+		readWorkspaces  = h.RequirePermission(entity.PermWorkspacesRead)
+		writeWorkspaces = h.RequirePermission(entity.PermWorkspacesWrite)
+		manageRoles     = h.RequirePermission(entity.PermRolesManage)
+		manageUsers     = h.RequirePermission(entity.PermUsersManage)
+
+		verifiedEmail = h.RequireVerifiedEmail()
+	)
 
 	// Защищённые
 	auth := r.Group("/")
-	auth.Use(AuthMiddleware())
+	auth.Use(AuthMiddleware(h.UserUseCase, h.RBACUseCase, h.WorkspaceUseCase))
 	{
-		auth.POST("/tasks", h.createTask)                  // создать задачу
-		auth.GET("/tasks", h.getTasks)                     // список моих задач
-		auth.GET("/tasks/:id", h.getTaskByID)              // получить одну задачу
-		auth.PUT("/tasks/:id", h.updateTask)               // обновить задачу
-		auth.PATCH("/tasks/:id/complete", h.completedTask) // отметить выполненной
-		auth.DELETE("/tasks/:id", h.deleteTask)            // удалить задачу
+		auth.POST("/auth/verify-email/resend", h.resendVerification) // письмо для подтверждения почты ещё раз
+		auth.POST("/auth/logout", h.logout)                          // завершить текущую сессию
+		auth.POST("/auth/logout-all", h.logoutAll)                   // завершить все сессии
+		auth.GET("/auth/sessions", h.getSessions)                    // где я вошёл
+		auth.DELETE("/auth/sessions/:id", h.revokeSession)           // завершить сессию
+
+		auth.POST("/tasks", writeTasks, verifiedEmail, h.createTask)                          // создать задачу
+		auth.GET("/tasks", readTasks, h.getTasks)                                             // список моих задач
+		auth.GET("/tasks/search", readTasks, h.searchTasks)                                   // полнотекстовый поиск
+		auth.GET("/tasks/:id", readTasks, h.getTaskByID)                                      // получить одну задачу
+		auth.PUT("/tasks/:id", writeTasks, h.updateTask)                                      // обновить задачу
+		auth.PATCH("/tasks/:id/complete", writeTasks, h.completedTask)                        // отметить выполненной
+		auth.POST("/tasks/:id/transitions", writeTasks, h.transitionTask)                     // сменить статус
+		auth.DELETE("/tasks/:id", deleteTasks, h.deleteTask)                                  // удалить задачу
+		auth.GET("/tasks/:id/subtasks", readTasks, h.getSubtasks)                             // подзадачи (или всё дерево)
+		auth.PUT("/tasks/:id/parent", writeTasks, h.setParent)                                // сделать подзадачей
+		auth.PUT("/tasks/:id/project", writeTasks, h.moveTask)                                // перенести в другой проект
+		auth.PUT("/tasks/:id/assignee", writeTasks, h.setAssignee)                            // назначить исполнителя
+		auth.POST("/tasks/:id/labels", writeTasks, h.attachLabels)                            // повесить метки
+		auth.DELETE("/tasks/:id/labels/:label_id", writeTasks, h.detachLabel)                 // снять метку
+		auth.POST("/tasks/:id/dependencies", writeTasks, h.addDependency)                     // добавить блокирующую задачу
+		auth.DELETE("/tasks/:id/dependencies/:depends_on_id", writeTasks, h.removeDependency) // убрать блокирующую задачу
+		auth.GET("/tasks/:id/dependency-graph", readTasks, h.getDependencyGraph)              // граф зависимостей
+		auth.PUT("/tasks/:id/recurrence", writeTasks, h.setRecurrence)                        // задать правило повторения
+		auth.GET("/tasks/:id/occurrences", readTasks, h.getOccurrences)                       // ближайшие повторения
+		auth.GET("/tasks/:id/reminders", readTasks, h.getReminders)                           // напоминания задачи
+		auth.POST("/tasks/:id/reminders", writeTasks, h.createReminder)                       // добавить напоминание
+		auth.DELETE("/tasks/:id/reminders/:reminder_id", writeTasks, h.deleteReminder)        // удалить напоминание
+		auth.GET("/tasks/:id/comments", readTasks, h.getComments)                             // комментарии задачи
+		auth.POST("/tasks/:id/comments", writeTasks, h.createComment)                         // написать комментарий
+		auth.PUT("/tasks/:id/comments/:comment_id", writeTasks, h.updateComment)              // изменить свой комментарий
+		auth.DELETE("/tasks/:id/comments/:comment_id", writeTasks, h.deleteComment)           // удалить комментарий
+		auth.GET("/tasks/:id/attachments", readTasks, h.getAttachments)                       // вложения задачи
+		auth.POST("/tasks/:id/attachments", writeTasks, h.uploadAttachment)                   // загрузить файл
+		auth.GET("/tasks/:id/attachments/:attachment_id", readTasks, h.downloadAttachment)    // скачать файл (Range)
+		auth.DELETE("/tasks/:id/attachments/:attachment_id", writeTasks, h.deleteAttachment)  // удалить файл
+		auth.GET("/tasks/:id/checklist", readTasks, h.getChecklist)                           // чек-лист задачи
+		auth.POST("/tasks/:id/checklist", writeTasks, h.addChecklistItem)                     // добавить пункт
+		auth.PUT("/tasks/:id/checklist/order", writeTasks, h.reorderChecklist)                // переставить пункты
+		auth.PATCH("/tasks/:id/checklist/:item_id", writeTasks, h.updateChecklistItem)        // отметить / изменить пункт
+		auth.DELETE("/tasks/:id/checklist/:item_id", writeTasks, h.deleteChecklistItem)       // удалить пункт
+		auth.GET("/tasks/:id/shares", readTasks, h.getTaskShares)                             // у кого есть доступ
+		auth.PUT("/tasks/:id/shares", writeTasks, h.shareTask)                                // выдать доступ по email
+		auth.DELETE("/tasks/:id/shares/:user_id", writeTasks, h.unshareTask)                  // отозвать доступ
+
+		auth.GET("/projects", readProjects, h.getProjects)                            // мои проекты
+		auth.POST("/projects", writeProjects, h.createProject)                        // создать проект
+		auth.GET("/projects/:id", readProjects, h.getProject)                         // один проект
+		auth.PUT("/projects/:id", writeProjects, h.updateProject)                     // изменить проект
+		auth.DELETE("/projects/:id", deleteProjects, h.deleteProject)                 // удалить проект
+		auth.GET("/projects/:id/tasks", readProjects, h.getProjectTasks)              // задачи проекта
+		auth.GET("/projects/:id/shares", readProjects, h.getProjectShares)            // у кого есть доступ
+		auth.PUT("/projects/:id/shares", writeProjects, h.shareProject)               // выдать доступ по email
+		auth.DELETE("/projects/:id/shares/:user_id", writeProjects, h.unshareProject) // отозвать доступ
+
+		auth.GET("/labels", readLabels, h.getLabels)           // мои метки
+		auth.POST("/labels", writeLabels, h.createLabel)       // создать метку
+		auth.GET("/labels/:id", readLabels, h.getLabel)        // одна метка
+		auth.PUT("/labels/:id", writeLabels, h.updateLabel)    // изменить метку
+		auth.DELETE("/labels/:id", writeLabels, h.deleteLabel) // удалить метку
+
+		auth.GET("/workflow", readWorkflow, h.getWorkflow)                              // мой workflow
+		auth.POST("/workflow/states", writeWorkflow, h.createWorkflowState)             // добавить свой статус
+		auth.DELETE("/workflow/states/:key", writeWorkflow, h.deleteWorkflowState)      // удалить свой статус
+		auth.POST("/workflow/transitions", writeWorkflow, h.addWorkflowTransition)      // разрешить переход
+		auth.DELETE("/workflow/transitions", writeWorkflow, h.deleteWorkflowTransition) // запретить свой переход
+
+		auth.GET("/workspaces", readWorkspaces, h.getWorkspaces)                                  // мои пространства
+		auth.POST("/workspaces", writeWorkspaces, h.createWorkspace)                              // создать пространство
+		auth.GET("/workspaces/:id/members", readWorkspaces, h.getWorkspaceMembers)                // участники
+		auth.PUT("/workspaces/:id/members/:user_id", writeWorkspaces, h.setWorkspaceMemberRole)   // сменить роль участника
+		auth.DELETE("/workspaces/:id/members/:user_id", writeWorkspaces, h.removeWorkspaceMember) // исключить / выйти
+		auth.POST("/workspaces/:id/invitations", writeWorkspaces, h.inviteToWorkspace)            // пригласить по email
+		auth.POST("/invitations/accept", writeWorkspaces, h.acceptInvitation)                     // принять приглашение
+	}
+
+	// Администрирование
+	admin := auth.Group("/admin")
+	{
+		admin.GET("/users", manageUsers, h.adminListUsers)                              // пользователи (поиск, страницы)
+		admin.GET("/users/:id", manageUsers, h.adminGetUser)                            // один пользователь
+		admin.GET("/users/:id/task-counts", manageUsers, h.adminUserTaskCounts)         // его задачи по статусам
+		admin.POST("/users/:id/disable", manageUsers, h.adminDisableUser)               // отключить аккаунт
+		admin.POST("/users/:id/enable", manageUsers, h.adminEnableUser)                 // включить аккаунт
+		admin.POST("/users/:id/password-reset", manageUsers, h.adminForcePasswordReset) // заставить сменить пароль
+		admin.DELETE("/users/:id", manageUsers, h.adminDeleteUser)                      // удалить пользователя
+
+		admin.GET("/roles", manageRoles, h.getRoles)                      // системные роли и разрешения
+		admin.GET("/users/:id/roles", manageRoles, h.getUserRoles)        // роли пользователя
+		admin.PUT("/users/:id/roles/:role", manageRoles, h.grantRole)     // выдать роль
+		admin.DELETE("/users/:id/roles/:role", manageRoles, h.revokeRole) // отозвать роль
 	}
 
 	return r, h
@@ -50,6 +201,16 @@ func getUserID(c *gin.Context) (int64, bool) {
 	return id, ok
 }
 
+// getClaims — claims токена, сохранённые AuthMiddleware.
+func getClaims(c *gin.Context) (*security.Claims, bool) {
+	v, ok := c.Get("claims")
+	if !ok {
+		return nil, false
+	}
+	claims, ok := v.(*security.Claims)
+	return claims, ok
+}
+
 func parseIDParam(c *gin.Context, name string) (int64, bool) {
 	s := c.Param(name)
 	id, err := strconv.ParseInt(s, 10, 64)
@@ -60,10 +221,139 @@ func parseIDParam(c *gin.Context, name string) (int64, bool) {
 	return id, true
 }
 
+// parseTaskQuery разбирает query-параметры списка задач.
+func (h *Handler) parseTaskQuery(c *gin.Context, userID int64) (entity.TaskQuery, bool) {
+	var f entity.TaskQuery
+	scope, ok := parseScope(c)
+	if !ok {
+		return f, false
+	}
+	f.Scope = scope
+	if v := c.Query("assigned_to"); v != "" {
+		assignee := userID
+		if v != "me" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid assigned_to, expected me or user id"})
+				return f, false
+			}
+			assignee = id
+		}
+		f.AssigneeID = &assignee
+	}
+	if v := c.Query("overdue"); v != "" {
+		overdue, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid overdue"})
+			return f, false
+		}
+		f.Overdue = overdue
+	}
+	if v := c.Query("due_before"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid due_before, expected RFC3339"})
+			return f, false
+		}
+		f.DueBefore = &t
+	}
+	if v := c.Query("labels"); v != "" {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				f.Labels = append(f.Labels, name)
+			}
+		}
+	}
+	sort, err := usecase.ParseTaskSort(c.Query("sort"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return f, false
+	}
+	f.Sort = sort
+
+	filter, err := h.TaskUseCase.ParseFilter(c.Request.Context(), userID, f, c.Query("filter"))
+	if err != nil {
+		var fe *usecase.FilterError
+		if errors.As(err, &fe) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fe.Error(), "position": fe.Pos, "token": fe.Token})
+			return f, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to parse filter"})
+		return f, false
+	}
+	f.Filter = filter
+	return f, true
+}
+
+// parseScope разбирает параметр scope: own (по умолчанию), shared или all.
+func parseScope(c *gin.Context) (entity.Scope, bool) {
+	switch scope := entity.Scope(c.Query("scope")); scope {
+	case "", entity.ScopeOwn, entity.ScopeShared, entity.ScopeAll:
+		return scope, true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scope, expected own, shared or all"})
+		return "", false
+	}
+}
+
+// parsePageRequest разбирает параметры постраничной выдачи.
+func parsePageRequest(c *gin.Context) (entity.PageRequest, bool) {
+	p := entity.PageRequest{Cursor: c.Query("cursor")}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return p, false
+		}
+		p.Limit = limit
+	}
+	if v := c.Query("with_total"); v != "" {
+		withTotal, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid with_total"})
+			return p, false
+		}
+		p.WithTotal = withTotal
+	}
+	return p, true
+}
+
+// writeTaskError переводит ошибку usecase в HTTP-ответ.
+func writeTaskError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
+	case errors.Is(err, usecase.ErrInvalidInput), errors.Is(err, usecase.ErrUnknownStatus),
+		errors.Is(err, usecase.ErrInvalidCursor), errors.Is(err, usecase.ErrInvalidRecurrence):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrFileTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrFileType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrLabelNotFound), errors.Is(err, usecase.ErrProjectNotFound),
+		errors.Is(err, usecase.ErrParentNotFound), errors.Is(err, usecase.ErrDependencyMissing),
+		errors.Is(err, usecase.ErrReminderNotFound), errors.Is(err, usecase.ErrCommentNotFound),
+		errors.Is(err, usecase.ErrAttachmentNotFound), errors.Is(err, usecase.ErrChecklistItemNotFound),
+		errors.Is(err, usecase.ErrUserNotFound), errors.Is(err, usecase.ErrShareNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrSubtaskCycle), errors.Is(err, usecase.ErrOpenSubtasks),
+		errors.Is(err, usecase.ErrHasSubtasks), errors.Is(err, usecase.ErrDependencyCycle),
+		errors.Is(err, usecase.ErrOpenBlockers), errors.Is(err, usecase.ErrChecklistConflict),
+		errors.Is(err, usecase.ErrSubtasksChanged):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
 // ===== auth =====
 
 // @Summary      Регистрация
-// @Description  Создаёт пользователя, хэширует пароль
+// @Description  Создаёт пользователя (пароль не короче 8 символов), хэширует пароль и отправляет на почту ссылку для её подтверждения
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -80,6 +370,10 @@ func (h *Handler) registerUser(c *gin.Context) {
 	}
 	id, err := h.UserUseCase.Register(c.Request.Context(), r.Email, r.Password, r.Description)
 	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -87,14 +381,15 @@ func (h *Handler) registerUser(c *gin.Context) {
 }
 
 // @Summary      Логин
-// @Description  Возвращает JWT при валидных email/пароле
+// @Description  Начинает новую сессию и возвращает короткоживущий JWT и refresh-токен для его обновления
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        request body LoginRequest true "payload"
-// @Success      200 {object} map[string]string "token"
+// @Success      200 {object} entity.AuthTokens
 // @Failure      400 {object} map[string]string
 // @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string "аккаунт отключён или нужно сбросить пароль"
 // @Router       /auth/login [post]
 func (h *Handler) login(c *gin.Context) {
 	var r LoginRequest
@@ -102,12 +397,99 @@ func (h *Handler) login(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
 		return
 	}
-	token, err := h.UserUseCase.Login(c.Request.Context(), r.Email, r.Password)
+	tokens, err := h.UserUseCase.Login(c.Request.Context(), r.Email, r.Password, c.Request.UserAgent(), c.ClientIP())
 	if err != nil {
+		if errors.Is(err, usecase.ErrAccountDisabled) || errors.Is(err, usecase.ErrPasswordResetRequired) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid email or password"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary      Обновить токены
+// @Description  Меняет refresh-токен на новую пару токенов; старый refresh-токен больше не действует. Повторное предъявление уже использованного токена завершает всю сессию.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body RefreshRequest true "payload"
+// @Success      200 {object} entity.AuthTokens
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string "токен недействителен, истёк или уже использован"
+// @Failure      403 {object} map[string]string "аккаунт отключён или нужно сбросить пароль"
+// @Failure      500 {object} map[string]string
+// @Router       /auth/refresh [post]
+func (h *Handler) refreshToken(c *gin.Context) {
+	var r RefreshRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	tokens, err := h.UserUseCase.Refresh(c.Request.Context(), r.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrRefreshTokenInvalid), errors.Is(err, usecase.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrAccountDisabled), errors.Is(err, usecase.ErrPasswordResetRequired):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to refresh token"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary      Забыл пароль
+// @Description  Отправляет на почту ссылку для сброса пароля, действующую час. Ответ одинаковый, есть такой аккаунт или нет; повторное письмо — не чаще раза в минуту.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body ForgotPasswordRequest true "payload"
+// @Success      202 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /auth/password/forgot [post]
+func (h *Handler) forgotPassword(c *gin.Context) {
+	var r ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&r); err != nil || r.Email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	if err := h.UserUseCase.ForgotPassword(c.Request.Context(), r.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to request password reset"})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "если аккаунт с этой почтой существует, на неё отправлена ссылка для сброса пароля"})
+}
+
+// @Summary      Сброс пароля
+// @Description  Задаёт новый пароль по одноразовому токену сброса из письма или от администратора. Все прежние сессии пользователя завершаются.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body ResetPasswordRequest true "payload"
+// @Success      204
+// @Failure      400 {object} map[string]string "токен недействителен или пароль слишком короткий"
+// @Failure      500 {object} map[string]string
+// @Router       /auth/password/reset [post]
+func (h *Handler) resetPassword(c *gin.Context) {
+	var r ResetPasswordRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	if err := h.UserUseCase.ResetPassword(c.Request.Context(), r.Token, r.Password); err != nil {
+		if errors.Is(err, usecase.ErrInvalidInput) || errors.Is(err, usecase.ErrResetTokenInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reset password"})
+		return
+	}
+	c.Status(http.StatusNoContent)
 }
 
 // ===== tasks =====
@@ -121,6 +503,7 @@ func (h *Handler) login(c *gin.Context) {
 // @Success      200 {object} entity.Task
 // @Failure      400 {object} map[string]string
 // @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string "нет доступа или почта не подтверждена"
 // @Failure      500 {object} map[string]string
 // @Router       /tasks [post]
 func (h *Handler) createTask(c *gin.Context) {
@@ -134,19 +517,41 @@ func (h *Handler) createTask(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
 		return
 	}
-	task, err := h.TaskUseCase.CreateTask(c.Request.Context(), userID, r.Title, r.Description)
+	task, err := h.TaskUseCase.CreateTask(c.Request.Context(), &entity.Task{
+		OwnerID:     userID,
+		ProjectID:   r.ProjectID,
+		ParentID:    r.ParentID,
+		Title:       r.Title,
+		Description: r.Description,
+		Priority:    r.Priority,
+		StartAt:     r.StartAt,
+		DueAt:       r.DueAt,
+		Recurrence:  r.Recurrence,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create task"})
+		writeTaskError(c, err, "failed to create task")
 		return
 	}
 	c.JSON(http.StatusOK, task)
 }
 
 // @Summary      Мои задачи
+// @Description  С scope=shared — чужие задачи, к которым мне выдан доступ (напрямую или через проект)
 // @Security     BearerAuth
 // @Tags         tasks
 // @Produce      json
+// @Param        scope       query string false "own (по умолчанию), shared или all" Enums(own, shared, all)
+// @Param        assigned_to query string false "me или id пользователя: только задачи этого исполнителя (по умолчанию ищет и среди чужих)"
+// @Param        filter      query string false "выражение фильтра, например: status:open AND priority>=high AND created_at>2026-01-01"
+// @Param        labels      query string false "имена меток через запятую; задача должна иметь все"
+// @Param        overdue     query bool   false "только просроченные открытые задачи"
+// @Param        due_before  query string false "срок раньше момента (RFC3339)"
+// @Param        limit       query int    false "размер страницы (по умолчанию 50, максимум 200)"
+// @Param        cursor      query string false "next_cursor из предыдущего ответа"
+// @Param        with_total  query bool   false "посчитать общее число задач"
+// @Param        sort        query string false "ключи через запятую, '-' — по убыванию: id, title, priority, start_at, due_at, created_at, updated_at"
 // @Success      200 {object} TasksResponse
+// @Failure      400 {object} map[string]string
 // @Failure      401 {object} map[string]string
 // @Failure      500 {object} map[string]string
 // @Router       /tasks [get]
@@ -156,12 +561,65 @@ func (h *Handler) getTasks(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
 		return
 	}
-	tasks, err := h.TaskUseCase.ListTasks(c.Request.Context(), userID)
+	h.listTasks(c, userID, nil)
+}
+
+// listTasks отвечает страницей задач по query-параметрам запроса;
+// projectID ограничивает выдачу одним проектом.
+func (h *Handler) listTasks(c *gin.Context, userID int64, projectID *int64) {
+	query, ok := h.parseTaskQuery(c, userID)
+	if !ok {
+		return
+	}
+	query.ProjectID = projectID
+	page, ok := parsePageRequest(c)
+	if !ok {
+		return
+	}
+	result, err := h.TaskUseCase.ListTasks(c.Request.Context(), userID, query, page)
+	if err != nil {
+		writeTaskError(c, err, "failed to list tasks")
+		return
+	}
+	c.JSON(http.StatusOK, TasksResponse{Tasks: result.Tasks, NextCursor: result.NextCursor, Total: result.Total})
+}
+
+// @Summary      Поиск задач
+// @Description  Полнотекстовый поиск по названию и описанию (русский и английский, поиск по началу слова). Фрагменты — HTML с <mark>.
+// @Security     BearerAuth
+// @Tags         tasks
+// @Produce      json
+// @Param        q       query string true  "поисковый запрос"
+// @Param        limit   query int    false "сколько результатов (по умолчанию 20, максимум 100)"
+// @Param        offset  query int    false "сколько результатов пропустить"
+// @Success      200 {object} SearchResponse
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/search [get]
+func (h *Handler) searchTasks(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+		return
+	}
+
+	hits, err := h.TaskUseCase.SearchTasks(c.Request.Context(), userID, c.Query("q"), limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list tasks"})
+		writeTaskError(c, err, "failed to search tasks")
 		return
 	}
-	c.JSON(http.StatusOK, TasksResponse{Tasks: tasks})
+	c.JSON(http.StatusOK, SearchResponse{Results: hits})
 }
 
 // @Summary      Одна задача
@@ -225,25 +683,24 @@ func (h *Handler) updateTask(c *gin.Context) {
 		return
 	}
 
-	task, err := h.TaskUseCase.UpdateTask(c.Request.Context(), &entity.Task{
+	task, err := h.TaskUseCase.UpdateTask(c.Request.Context(), userID, &entity.Task{
 		ID:          taskID,
-		OwnerID:     userID,
 		Title:       r.Title,
 		Description: r.Description,
 		Status:      r.Status,
+		Priority:    r.Priority,
+		StartAt:     r.StartAt,
+		DueAt:       r.DueAt,
 	})
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update task"})
+		writeTaskError(c, err, "failed to update task")
 		return
 	}
 	c.JSON(http.StatusOK, task)
 }
 
 // @Summary      Отметить выполненной
+// @Description  Для повторяющейся задачи создаётся следующее повторение со сдвинутым сроком; оно возвращается в next_occurrence
 // @Security     BearerAuth
 // @Tags         tasks
 // @Produce      json
@@ -264,29 +721,100 @@ func (h *Handler) completedTask(c *gin.Context) {
 		return
 	}
 
-	task, err := h.TaskUseCase.UpdateTask(c.Request.Context(), &entity.Task{
-		ID:      taskID,
-		OwnerID: userID,
-		Status:  true,
-	})
+	task, err := h.TaskUseCase.CompleteTask(c.Request.Context(), taskID, userID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to complete task"})
+		writeTaskError(c, err, "failed to complete task")
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+// @Summary      Сменить статус
+// @Description  Переводит задачу в новое состояние; недопустимые по workflow переходы отклоняются с 409
+// @Security     BearerAuth
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        id   path int true "Task ID"
+// @Param        request body TransitionRequest true "payload"
+// @Success      200 {object} entity.Task
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/transitions [post]
+func (h *Handler) transitionTask(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var r TransitionRequest
+	if err := c.ShouldBindJSON(&r); err != nil || r.To == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	task, err := h.TaskUseCase.TransitionTask(c.Request.Context(), taskID, userID, r.To)
+	if err != nil {
+		writeTaskError(c, err, "failed to change task status")
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+// @Summary      Перенести задачу в проект
+// @Description  project_id: null убирает задачу из проекта
+// @Security     BearerAuth
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        id   path int true "Task ID"
+// @Param        request body MoveTaskRequest true "payload"
+// @Success      200 {object} entity.Task
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/project [put]
+func (h *Handler) moveTask(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var r MoveTaskRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	task, err := h.TaskUseCase.MoveTask(c.Request.Context(), taskID, userID, r.ProjectID)
+	if err != nil {
+		writeTaskError(c, err, "failed to move task")
 		return
 	}
 	c.JSON(http.StatusOK, task)
 }
 
 // @Summary      Удалить задачу
+// @Description  Подзадачи удаляются, отвязываются или блокируют удаление — в зависимости от SUBTASK_ON_DELETE
 // @Security     BearerAuth
 // @Tags         tasks
 // @Param        id   path int true "Task ID"
 // @Success      204  "no content"
 // @Failure      401 {object} map[string]string
 // @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
 // @Failure      500 {object} map[string]string
 // @Router       /tasks/{id} [delete]
 func (h *Handler) deleteTask(c *gin.Context) {
@@ -301,11 +829,7 @@ func (h *Handler) deleteTask(c *gin.Context) {
 	}
 
 	if err := h.TaskUseCase.DeleteTask(c.Request.Context(), taskID, userID); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "task not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete task"})
+		writeTaskError(c, err, "failed to delete task")
 		return
 	}
 	c.Status(http.StatusNoContent)
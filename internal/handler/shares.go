@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"app/internal/entity"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// writeShareError отвечает ошибкой в формате ресурса, к которому выдаётся доступ.
+func writeShareError(c *gin.Context, target entity.ShareTarget, err error, fallback string) {
+	if target == entity.ShareProject {
+		writeProjectError(c, err, fallback)
+		return
+	}
+	writeTaskError(c, err, fallback)
+}
+
+func (h *Handler) listShares(c *gin.Context, target entity.ShareTarget) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	shares, err := h.ShareUseCase.ListShares(c.Request.Context(), target, id, userID)
+	if err != nil {
+		writeShareError(c, target, err, "failed to list shares")
+		return
+	}
+	c.JSON(http.StatusOK, SharesResponse{Shares: shares})
+}
+
+func (h *Handler) share(c *gin.Context, target entity.ShareTarget) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var r ShareRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	shares, err := h.ShareUseCase.Share(c.Request.Context(), target, id, userID, r.Email, r.Role)
+	if err != nil {
+		writeShareError(c, target, err, "failed to share")
+		return
+	}
+	c.JSON(http.StatusOK, SharesResponse{Shares: shares})
+}
+
+func (h *Handler) unshare(c *gin.Context, target entity.ShareTarget) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	memberID, ok := parseIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+	if err := h.ShareUseCase.Unshare(c.Request.Context(), target, id, userID, memberID); err != nil {
+		writeShareError(c, target, err, "failed to unshare")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary      Доступ к задаче
+// @Description  Кому выдан доступ к задаче; владелец задачи в список не входит
+// @Security     BearerAuth
+// @Tags         shares
+// @Produce      json
+// @Param        id   path int true "Task ID"
+// @Success      200 {object} SharesResponse
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/shares [get]
+func (h *Handler) getTaskShares(c *gin.Context) {
+	h.listShares(c, entity.ShareTask)
+}
+
+// @Summary      Выдать доступ к задаче
+// @Description  viewer — только чтение, editor — изменение, owner — ещё удаление, перенос и управление доступом. Нужна роль owner.
+// @Security     BearerAuth
+// @Tags         shares
+// @Accept       json
+// @Produce      json
+// @Param        id   path int true "Task ID"
+// @Param        request body ShareRequest true "payload"
+// @Success      200 {object} SharesResponse
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/shares [put]
+func (h *Handler) shareTask(c *gin.Context) {
+	h.share(c, entity.ShareTask)
+}
+
+// @Summary      Отозвать доступ к задаче
+// @Description  Чужой доступ отзывает владелец; от своего доступа можно отказаться самому
+// @Security     BearerAuth
+// @Tags         shares
+// @Param        id       path int true "Task ID"
+// @Param        user_id  path int true "User ID"
+// @Success      204
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/shares/{user_id} [delete]
+func (h *Handler) unshareTask(c *gin.Context) {
+	h.unshare(c, entity.ShareTask)
+}
+
+// @Summary      Доступ к проекту
+// @Description  Доступ к проекту распространяется на все его задачи
+// @Security     BearerAuth
+// @Tags         shares
+// @Produce      json
+// @Param        id   path int true "Project ID"
+// @Success      200 {object} SharesResponse
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /projects/{id}/shares [get]
+func (h *Handler) getProjectShares(c *gin.Context) {
+	h.listShares(c, entity.ShareProject)
+}
+
+// @Summary      Выдать доступ к проекту
+// @Description  Роль действует на проект и все его задачи. Нужна роль owner.
+// @Security     BearerAuth
+// @Tags         shares
+// @Accept       json
+// @Produce      json
+// @Param        id   path int true "Project ID"
+// @Param        request body ShareRequest true "payload"
+// @Success      200 {object} SharesResponse
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /projects/{id}/shares [put]
+func (h *Handler) shareProject(c *gin.Context) {
+	h.share(c, entity.ShareProject)
+}
+
+// @Summary      Отозвать доступ к проекту
+// @Security     BearerAuth
+// @Tags         shares
+// @Param        id       path int true "Project ID"
+// @Param        user_id  path int true "User ID"
+// @Success      204
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /projects/{id}/shares/{user_id} [delete]
+func (h *Handler) unshareProject(c *gin.Context) {
+	h.unshare(c, entity.ShareProject)
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+// @Summary      Подзадачи
+// @Description  Прямые подзадачи с фильтрами и пагинацией как у GET /tasks; с tree=true — задача со всем деревом потомков (entity.TaskTree)
+// @Security     BearerAuth
+// @Tags         tasks
+// @Produce      json
+// @Param        id    path  int  true  "Task ID"
+// @Param        tree  query bool false "вернуть всё дерево"
+// @Success      200 {object} TasksResponse
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/subtasks [get]
+func (h *Handler) getSubtasks(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	tree := false
+	if v := c.Query("tree"); v != "" {
+		var err error
+		if tree, err = strconv.ParseBool(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tree"})
+			return
+		}
+	}
+
+	if tree {
+		result, err := h.TaskUseCase.SubtaskTree(c.Request.Context(), taskID, userID)
+		if err != nil {
+			writeTaskError(c, err, "failed to get subtasks")
+			return
+		}
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
+	query, ok := h.parseTaskQuery(c, userID)
+	if !ok {
+		return
+	}
+	page, ok := parsePageRequest(c)
+	if !ok {
+		return
+	}
+	result, err := h.TaskUseCase.ListSubtasks(c.Request.Context(), taskID, userID, query, page)
+	if err != nil {
+		writeTaskError(c, err, "failed to get subtasks")
+		return
+	}
+	c.JSON(http.StatusOK, TasksResponse{Tasks: result.Tasks, NextCursor: result.NextCursor, Total: result.Total})
+}
+
+// @Summary      Сделать подзадачей
+// @Description  parent_id: null делает задачу корневой. Задачу нельзя подчинить ей самой или её потомку.
+// @Security     BearerAuth
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        id   path int true "Task ID"
+// @Param        request body SetParentRequest true "payload"
+// @Success      200 {object} entity.Task
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /tasks/{id}/parent [put]
+func (h *Handler) setParent(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	taskID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var r SetParentRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	task, err := h.TaskUseCase.SetParent(c.Request.Context(), taskID, userID, r.ParentID)
+	if err != nil {
+		writeTaskError(c, err, "failed to set parent")
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
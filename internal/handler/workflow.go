@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"app/internal/entity"
+	"app/internal/usecase"
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// writeWorkflowError переводит ошибку WorkflowUseCase в HTTP-ответ.
+func writeWorkflowError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	case errors.Is(err, usecase.ErrInvalidInput), errors.Is(err, usecase.ErrUnknownStatus):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrStateExists), errors.Is(err, usecase.ErrStateInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// @Summary      Мой workflow
+// @Description  Встроенные и пользовательские статусы и разрешённые переходы между ними
+// @Security     BearerAuth
+// @Tags         workflow
+// @Produce      json
+// @Success      200 {object} entity.Workflow
+// @Failure      401 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /workflow [get]
+func (h *Handler) getWorkflow(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	wf, err := h.WorkflowUseCase.GetWorkflow(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get workflow"})
+		return
+	}
+	c.JSON(http.StatusOK, wf)
+}
+
+// @Summary      Добавить статус
+// @Security     BearerAuth
+// @Tags         workflow
+// @Accept       json
+// @Produce      json
+// @Param        request body CreateStateRequest true "payload"
+// @Success      201 {object} entity.WorkflowState
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /workflow/states [post]
+func (h *Handler) createWorkflowState(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	var r CreateStateRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	state, err := h.WorkflowUseCase.CreateState(c.Request.Context(), userID, r.Key, r.Name, r.Category)
+	if err != nil {
+		writeWorkflowError(c, err, "failed to create state")
+		return
+	}
+	c.JSON(http.StatusCreated, state)
+}
+
+// @Summary      Удалить статус
+// @Description  Удаляет пользовательский статус, если ни одна задача в нём не находится
+// @Security     BearerAuth
+// @Tags         workflow
+// @Param        key  path string true "State key"
+// @Success      204  "no content"
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /workflow/states/{key} [delete]
+func (h *Handler) deleteWorkflowState(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	key := entity.TaskStatus(c.Param("key"))
+	if err := h.WorkflowUseCase.DeleteState(c.Request.Context(), userID, key); err != nil {
+		writeWorkflowError(c, err, "failed to delete state")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary      Разрешить переход
+// @Security     BearerAuth
+// @Tags         workflow
+// @Accept       json
+// @Param        request body WorkflowTransitionRequest true "payload"
+// @Success      204  "no content"
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /workflow/transitions [post]
+func (h *Handler) addWorkflowTransition(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	var r WorkflowTransitionRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	if err := h.WorkflowUseCase.AddTransition(c.Request.Context(), userID, r.From, r.To); err != nil {
+		writeWorkflowError(c, err, "failed to add transition")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary      Запретить переход
+// @Description  Удаляет пользовательский переход; встроенные переходы не удаляются
+// @Security     BearerAuth
+// @Tags         workflow
+// @Accept       json
+// @Param        request body WorkflowTransitionRequest true "payload"
+// @Success      204  "no content"
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /workflow/transitions [delete]
+func (h *Handler) deleteWorkflowTransition(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	var r WorkflowTransitionRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	if err := h.WorkflowUseCase.DeleteTransition(c.Request.Context(), userID, r.From, r.To); err != nil {
+		writeWorkflowError(c, err, "failed to delete transition")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
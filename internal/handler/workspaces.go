@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"app/internal/usecase"
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// writeWorkspaceError переводит ошибку usecase пространств в HTTP-ответ.
+func writeWorkspaceError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "workspace not found"})
+	case errors.Is(err, usecase.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrMemberNotFound), errors.Is(err, usecase.ErrInvitationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrLastAdmin):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// @Summary      Мои рабочие пространства
+// @Description  Пространства, где пользователь участник, с его ролью; личное — первым. Пространство запроса выбирается заголовком X-Workspace-ID, без него — личное.
+// @Security     BearerAuth
+// @Tags         workspaces
+// @Produce      json
+// @Success      200 {object} WorkspacesResponse
+// @Failure      401 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /workspaces [get]
+func (h *Handler) getWorkspaces(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	workspaces, err := h.WorkspaceUseCase.ListWorkspaces(c.Request.Context(), userID)
+	if err != nil {
+		writeWorkspaceError(c, err, "failed to list workspaces")
+		return
+	}
+	c.JSON(http.StatusOK, WorkspacesResponse{Workspaces: workspaces})
+}
+
+// @Summary      Создать рабочее пространство
+// @Description  Создатель становится администратором
+// @Security     BearerAuth
+// @Tags         workspaces
+// @Accept       json
+// @Produce      json
+// @Param        request body CreateWorkspaceRequest true "payload"
+// @Success      201 {object} entity.Workspace
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /workspaces [post]
+func (h *Handler) createWorkspace(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	var r CreateWorkspaceRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	workspace, err := h.WorkspaceUseCase.CreateWorkspace(c.Request.Context(), userID, r.Name)
+	if err != nil {
+		writeWorkspaceError(c, err, "failed to create workspace")
+		return
+	}
+	c.JSON(http.StatusCreated, workspace)
+}
+
+// @Summary      Участники пространства
+// @Security     BearerAuth
+// @Tags         workspaces
+// @Produce      json
+// @Param        id   path int true "Workspace ID"
+// @Success      200 {object} MembersResponse
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /workspaces/{id}/members [get]
+func (h *Handler) getWorkspaceMembers(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	workspaceID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	members, err := h.WorkspaceUseCase.ListMembers(c.Request.Context(), workspaceID, userID)
+	if err != nil {
+		writeWorkspaceError(c, err, "failed to list members")
+		return
+	}
+	c.JSON(http.StatusOK, MembersResponse{Members: members})
+}
+
+// @Summary      Сменить роль участника
+// @Description  admin управляет участниками и имеет права владельца на все задачи и проекты пространства, member заводит свои, guest работает только с выданными ему. Нужна роль admin; последнего администратора разжаловать нельзя.
+// @Security     BearerAuth
+// @Tags         workspaces
+// @Accept       json
+// @Produce      json
+// @Param        id       path int true "Workspace ID"
+// @Param        user_id  path int true "User ID"
+// @Param        request body SetMemberRoleRequest true "payload"
+// @Success      200 {object} entity.Membership
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /workspaces/{id}/members/{user_id} [put]
+func (h *Handler) setWorkspaceMemberRole(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	workspaceID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	memberID, ok := parseIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+	var r SetMemberRoleRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	member, err := h.WorkspaceUseCase.SetMemberRole(c.Request.Context(), workspaceID, userID, memberID, r.Role)
+	if err != nil {
+		writeWorkspaceError(c, err, "failed to set member role")
+		return
+	}
+	c.JSON(http.StatusOK, member)
+}
+
+// @Summary      Исключить участника
+// @Description  Чужое членство снимает администратор; выйти из пространства можно самому. Доступы участника к задачам и проектам пространства отзываются.
+// @Security     BearerAuth
+// @Tags         workspaces
+// @Param        id       path int true "Workspace ID"
+// @Param        user_id  path int true "User ID"
+// @Success      204
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      409 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /workspaces/{id}/members/{user_id} [delete]
+func (h *Handler) removeWorkspaceMember(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	workspaceID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	memberID, ok := parseIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+	if err := h.WorkspaceUseCase.RemoveMember(c.Request.Context(), workspaceID, userID, memberID); err != nil {
+		writeWorkspaceError(c, err, "failed to remove member")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary      Пригласить в пространство
+// @Description  Токен приглашения возвращается только в этом ответе и действует 7 дней. Нужна роль admin.
+// @Security     BearerAuth
+// @Tags         workspaces
+// @Accept       json
+// @Produce      json
+// @Param        id   path int true "Workspace ID"
+// @Param        request body InviteRequest true "payload"
+// @Success      201 {object} entity.Invitation
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /workspaces/{id}/invitations [post]
+func (h *Handler) inviteToWorkspace(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	workspaceID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var r InviteRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	inv, err := h.WorkspaceUseCase.Invite(c.Request.Context(), workspaceID, userID, r.Email, r.Role)
+	if err != nil {
+		writeWorkspaceError(c, err, "failed to invite")
+		return
+	}
+	c.JSON(http.StatusCreated, inv)
+}
+
+// @Summary      Принять приглашение
+// @Description  Приглашение одноразовое и действует только для почты, на которую выписано
+// @Security     BearerAuth
+// @Tags         workspaces
+// @Accept       json
+// @Produce      json
+// @Param        request body AcceptInvitationRequest true "payload"
+// @Success      200 {object} entity.Membership
+// @Failure      400 {object} map[string]string
+// @Failure      401 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /invitations/accept [post]
+func (h *Handler) acceptInvitation(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user in context"})
+		return
+	}
+	var r AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	member, err := h.WorkspaceUseCase.AcceptInvitation(c.Request.Context(), userID, r.Token)
+	if err != nil {
+		writeWorkspaceError(c, err, "failed to accept invitation")
+		return
+	}
+	c.JSON(http.StatusOK, member)
+}
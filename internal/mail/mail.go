@@ -0,0 +1,144 @@
+package mail
+
+import (
+	"app/internal/entity"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogMailer пишет письма в лог; используется в разработке, когда SMTP не настроен.
+type LogMailer struct{}
+
+func (LogMailer) Send(_ context.Context, msg entity.Email) error {
+	log.Printf("mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// SMTPMailer отправляет письма через SMTP-сервер. STARTTLS включается, если
+// сервер его поддерживает; без имени пользователя письма уходят без
+// авторизации — так удобно проверять отправку на локальной ловушке писем.
+type SMTPMailer struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     mail.Address
+	timeout  time.Duration
+}
+
+func NewSMTPMailer(host string, port int, username, password, from string) (*SMTPMailer, error) {
+	addr, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %w", from, err)
+	}
+	return &SMTPMailer{
+		addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		host:     host,
+		username: username,
+		password: password,
+		from:     *addr,
+		timeout:  30 * time.Second,
+	}, nil
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg entity.Email) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient %q: %w", msg.To, err)
+	}
+	body, err := m.message(to, msg)
+	if err != nil {
+		return err
+	}
+
+	dialer := net.Dialer{Timeout: m.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(m.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(m.from.Address); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// message собирает письмо: UTF-8 текст в quoted-printable, тема в
+// кодировке RFC 2047.
+func (m *SMTPMailer) message(to *mail.Address, msg entity.Email) ([]byte, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	domain := m.from.Address[strings.LastIndex(m.from.Address, "@")+1:]
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", m.from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id), domain)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(msg.Body, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,307 @@
+package mail
+
+import (
+	"app/internal/entity"
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpSink — минимальный SMTP-сервер для одного соединения: записывает
+// конверт, данные AUTH PLAIN и текст письма. rejectRcpt отклоняет получателя.
+type smtpSink struct {
+	ln         net.Listener
+	rejectRcpt bool
+
+	done     chan struct{}
+	from     string
+	rcpt     []string
+	auth     string
+	data     []byte
+	commands []string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpSink{ln: ln, done: make(chan struct{})}
+	t.Cleanup(func() { ln.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *smtpSink) hostPort(t *testing.T) (string, int) {
+	host, port, err := net.SplitHostPort(s.ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := strconv.Atoi(port)
+	return host, p
+}
+
+func (s *smtpSink) serve() {
+	defer close(s.done)
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	tp := textproto.NewConn(conn)
+
+	tp.PrintfLine("220 sink ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		s.commands = append(s.commands, line)
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			tp.PrintfLine("250-sink")
+			tp.PrintfLine("250-8BITMIME")
+			tp.PrintfLine("250 AUTH PLAIN")
+		case "AUTH":
+			_, resp, _ := strings.Cut(arg, " ")
+			b, _ := base64.StdEncoding.DecodeString(resp)
+			s.auth = string(b)
+			tp.PrintfLine("235 ok")
+		case "MAIL":
+			s.from = arg
+			tp.PrintfLine("250 ok")
+		case "RCPT":
+			if s.rejectRcpt {
+				tp.PrintfLine("550 no such user")
+				continue
+			}
+			s.rcpt = append(s.rcpt, arg)
+			tp.PrintfLine("250 ok")
+		case "DATA":
+			tp.PrintfLine("354 go ahead")
+			s.data, err = io.ReadAll(tp.DotReader())
+			if err != nil {
+				return
+			}
+			tp.PrintfLine("250 queued")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("250 ok")
+		}
+	}
+}
+
+func (s *smtpSink) wait(t *testing.T) {
+	t.Helper()
+	select {
+	case <-s.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("SMTP session did not finish")
+	}
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	sink := newSMTPSink(t)
+	host, port := sink.hostPort(t)
+	m, err := NewSMTPMailer(host, port, "", "", "Трекер задач <noreply@tasks.example>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = m.Send(context.Background(), entity.Email{
+		To:      "Иван Петров <ivan@example.com>",
+		Subject: "Сброс пароля",
+		Body:    "Привет!\nСсылка: https://tasks.example/reset?token=abc=def",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	sink.wait(t)
+
+	if !strings.HasPrefix(sink.from, "FROM:<noreply@tasks.example>") {
+		t.Errorf("MAIL %q", sink.from)
+	}
+	if len(sink.rcpt) != 1 || sink.rcpt[0] != "TO:<ivan@example.com>" {
+		t.Errorf("RCPT %q", sink.rcpt)
+	}
+	for _, cmd := range sink.commands {
+		if strings.HasPrefix(cmd, "AUTH") {
+			t.Errorf("unexpected %q without username", cmd)
+		}
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(sink.data)))
+	if err != nil {
+		t.Fatalf("parse message: %v\n%s", err, sink.data)
+	}
+	h := msg.Header
+
+	// заголовки с не-ASCII должны идти в кодировке RFC 2047, а не сырым UTF-8
+	for _, name := range []string{"From", "To", "Subject"} {
+		v := h.Get(name)
+		if v == "" {
+			t.Errorf("missing %s header", name)
+		}
+		for _, r := range v {
+			if r > 127 {
+				t.Errorf("%s header is not encoded: %q", name, v)
+				break
+			}
+		}
+	}
+
+	to, err := h.AddressList("To")
+	if err != nil || len(to) != 1 || to[0].Name != "Иван Петров" || to[0].Address != "ivan@example.com" {
+		t.Errorf("To %q: %v %v", h.Get("To"), to, err)
+	}
+	from, err := h.AddressList("From")
+	if err != nil || len(from) != 1 || from[0].Name != "Трекер задач" || from[0].Address != "noreply@tasks.example" {
+		t.Errorf("From %q: %v %v", h.Get("From"), from, err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(h.Get("Subject"))
+	if err != nil || subject != "Сброс пароля" {
+		t.Errorf("Subject %q decodes to %q (%v)", h.Get("Subject"), subject, err)
+	}
+	if _, err := h.Date(); err != nil {
+		t.Errorf("Date %q: %v", h.Get("Date"), err)
+	}
+	if id := h.Get("Message-ID"); !strings.HasPrefix(id, "<") || !strings.HasSuffix(id, "@tasks.example>") {
+		t.Errorf("Message-ID %q", id)
+	}
+	if got := h.Get("Content-Transfer-Encoding"); got != "quoted-printable" {
+		t.Errorf("Content-Transfer-Encoding %q", got)
+	}
+
+	if !strings.Contains(string(sink.data), "token=3D") {
+		t.Errorf("body is not quoted-printable:\n%s", sink.data)
+	}
+	// DotReader сервера уже привёл переводы строк к \n
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Привет!\nСсылка: https://tasks.example/reset?token=abc=def"
+	if got := strings.TrimSuffix(string(body), "\n"); got != want {
+		t.Errorf("body %q, want %q", got, want)
+	}
+}
+
+func TestSMTPMailerAuth(t *testing.T) {
+	sink := newSMTPSink(t)
+	host, port := sink.hostPort(t)
+	m, err := NewSMTPMailer(host, port, "mailer", "s3cret", "noreply@tasks.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Send(context.Background(), entity.Email{To: "ivan@example.com", Subject: "hi", Body: "hi"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	sink.wait(t)
+	if sink.auth != "\x00mailer\x00s3cret" {
+		t.Errorf("AUTH PLAIN %q", sink.auth)
+	}
+}
+
+func TestSMTPMailerErrors(t *testing.T) {
+	t.Run("recipient rejected", func(t *testing.T) {
+		sink := newSMTPSink(t)
+		sink.rejectRcpt = true
+		host, port := sink.hostPort(t)
+		m, err := NewSMTPMailer(host, port, "", "", "noreply@tasks.example")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = m.Send(context.Background(), entity.Email{To: "nobody@example.com", Subject: "x", Body: "x"})
+		var tpErr *textproto.Error
+		if !errors.As(err, &tpErr) || tpErr.Code != 550 {
+			t.Fatalf("Send: got %v, want 550 from server", err)
+		}
+		if sink.data != nil {
+			t.Error("message data was sent after RCPT was rejected")
+		}
+	})
+
+	t.Run("invalid recipient", func(t *testing.T) {
+		m, err := NewSMTPMailer("127.0.0.1", 1, "", "", "noreply@tasks.example")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Send(context.Background(), entity.Email{To: "not an address"}); err == nil {
+			t.Fatal("expected error for invalid recipient")
+		}
+	})
+
+	t.Run("server unavailable", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr := ln.Addr().(*net.TCPAddr)
+		ln.Close()
+
+		m, err := NewSMTPMailer("127.0.0.1", addr.Port, "", "", "noreply@tasks.example")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Send(context.Background(), entity.Email{To: "ivan@example.com", Subject: "x", Body: "x"}); err == nil {
+			t.Fatal("expected dial error")
+		}
+	})
+
+	t.Run("invalid sender", func(t *testing.T) {
+		if _, err := NewSMTPMailer("127.0.0.1", 25, "", "", "not an address"); err == nil {
+			t.Fatal("expected error for invalid sender")
+		}
+	})
+}
+
+// Сервер, который молчит после приветствия, не должен подвешивать Send
+// дольше дедлайна контекста.
+func TestSMTPMailerContextDeadline(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		bufio.NewReader(conn).ReadString('\n') // ни приветствия, ни ответа
+		time.Sleep(2 * time.Second)
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	m, err := NewSMTPMailer("127.0.0.1", addr.Port, "", "", "noreply@tasks.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := m.Send(ctx, entity.Email{To: "ivan@example.com", Subject: "x", Body: "x"}); err == nil {
+		t.Fatal("expected timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Send took %v, want it to stop at the context deadline", elapsed)
+	}
+}
@@ -0,0 +1,162 @@
+// Package markdown рендерит подмножество Markdown в безопасный HTML.
+//
+// Исходный текст сначала целиком экранируется, а разметку добавляет только
+// сам рендерер, поэтому сырой HTML из ввода в результат не попадает.
+// Поддерживаются абзацы, заголовки, цитаты, списки, блоки кода,
+// `код`, **жирный**, *курсив*, ~~зачёркнутый~~ и ссылки [текст](url)
+// со схемами http, https и mailto.
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	headingRe   = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	bulletRe    = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	orderedRe   = regexp.MustCompile(`^\s*\d+[.)]\s+(.*)$`)
+	linkRe      = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	strongRe    = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*`)
+	emRe        = regexp.MustCompile(`\*(\S(?:.*?\S)?)\*|\b_(\S(?:.*?\S)?)_\b`)
+	strikeRe    = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+	slotRe      = regexp.MustCompile("\x00(\\d+)\x00")
+	safeSchemes = []string{"http://", "https://", "mailto:"}
+)
+
+// Render возвращает HTML для текста src.
+func Render(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\x00", "")
+	lines := strings.Split(src, "\n")
+
+	var (
+		out       strings.Builder
+		paragraph []string
+	)
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		parts := make([]string, len(paragraph))
+		for i, l := range paragraph {
+			parts[i] = inline(strings.TrimSpace(l))
+		}
+		out.WriteString("<p>" + strings.Join(parts, "<br>\n") + "</p>\n")
+		paragraph = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			out.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+
+		case headingRe.MatchString(trimmed):
+			flush()
+			m := headingRe.FindStringSubmatch(trimmed)
+			tag := "h" + strconv.Itoa(len(m[1]))
+			out.WriteString("<" + tag + ">" + inline(m[2]) + "</" + tag + ">\n")
+
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quote = append(quote, strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">"), " "))
+			}
+			i--
+			out.WriteString("<blockquote>\n" + Render(strings.Join(quote, "\n")) + "</blockquote>\n")
+
+		case bulletRe.MatchString(line), orderedRe.MatchString(line):
+			flush()
+			re, tag := bulletRe, "ul"
+			if !bulletRe.MatchString(line) {
+				re, tag = orderedRe, "ol"
+			}
+			out.WriteString("<" + tag + ">\n")
+			for ; i < len(lines) && re.MatchString(lines[i]); i++ {
+				out.WriteString("<li>" + inline(re.FindStringSubmatch(lines[i])[1]) + "</li>\n")
+			}
+			i--
+			out.WriteString("</" + tag + ">\n")
+
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	flush()
+	return out.String()
+}
+
+// inline обрабатывает разметку внутри строки. Код и ссылки сначала
+// заменяются метками \x00N\x00, чтобы выделение не задевало их содержимое.
+func inline(s string) string {
+	var slots []string
+	slot := func(h string) string {
+		slots = append(slots, h)
+		return "\x00" + strconv.Itoa(len(slots)-1) + "\x00"
+	}
+
+	// `код`: нечётные части между обратными кавычками
+	parts := strings.Split(s, "`")
+	var b strings.Builder
+	for i, p := range parts {
+		switch {
+		case i%2 == 0:
+			b.WriteString(html.EscapeString(p))
+		case i == len(parts)-1:
+			// незакрытая кавычка — обычный текст
+			b.WriteString("`" + html.EscapeString(p))
+		default:
+			b.WriteString(slot("<code>" + html.EscapeString(p) + "</code>"))
+		}
+	}
+	s = b.String()
+
+	s = linkRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := linkRe.FindStringSubmatch(m)
+		text, href := emphasis(sub[1]), sub[2]
+		if !safeURL(html.UnescapeString(href)) {
+			return text
+		}
+		return slot(`<a href="` + href + `" rel="nofollow noopener noreferrer">` + text + `</a>`)
+	})
+
+	s = emphasis(s)
+	// метки могут быть вложены: код внутри текста ссылки
+	for slotRe.MatchString(s) {
+		s = slotRe.ReplaceAllStringFunc(s, func(m string) string {
+			n, _ := strconv.Atoi(slotRe.FindStringSubmatch(m)[1])
+			return slots[n]
+		})
+	}
+	return s
+}
+
+func emphasis(s string) string {
+	s = strongRe.ReplaceAllString(s, "<strong>$1</strong>")
+	s = emRe.ReplaceAllString(s, "<em>$1$2</em>")
+	return strikeRe.ReplaceAllString(s, "<del>$1</del>")
+}
+
+func safeURL(u string) bool {
+	u = strings.ToLower(strings.TrimSpace(u))
+	for _, scheme := range safeSchemes {
+		if strings.HasPrefix(u, scheme) {
+			return true
+		}
+	}
+	return false
+}
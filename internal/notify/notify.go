@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"app/internal/entity"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// LogNotifier пишет напоминания в лог; используется, когда доставка не настроена.
+type LogNotifier struct{}
+
+func (LogNotifier) NotifyReminder(_ context.Context, task *entity.Task, reminder *entity.Reminder) error {
+	log.Printf("reminder %d: user %d, task %d %q, due %v", reminder.ID, task.OwnerID, task.ID, task.Title, task.DueAt)
+	return nil
+}
+
+// WebhookNotifier отправляет напоминание POST-запросом с JSON. Доставка
+// at-least-once, поэтому получатель должен отбрасывать повторы по
+// заголовку Idempotency-Key.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+type reminderPayload struct {
+	ReminderID int64      `json:"reminder_id"`
+	TaskID     int64      `json:"task_id"`
+	UserID     int64      `json:"user_id"`
+	Title      string     `json:"title"`
+	DueAt      *time.Time `json:"due_at"`
+	FireAt     *time.Time `json:"fire_at"`
+}
+
+func (n *WebhookNotifier) NotifyReminder(ctx context.Context, task *entity.Task, reminder *entity.Reminder) error {
+	body, err := json.Marshal(reminderPayload{
+		ReminderID: reminder.ID,
+		TaskID:     task.ID,
+		UserID:     task.OwnerID,
+		Title:      task.Title,
+		DueAt:      task.DueAt,
+		FireAt:     reminder.FireAt,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", "reminder-"+strconv.FormatInt(reminder.ID, 10))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"app/internal/entity"
+	"app/internal/tenant"
+	"context"
+	"database/sql"
+)
+
+type AttachmentRepo struct {
+	db *sql.DB
+}
+
+func NewAttachmentRepo(db *sql.DB) *AttachmentRepo {
+	return &AttachmentRepo{db: db}
+}
+
+// attachmentColumns рассчитаны на псевдоним a: запросы соединяют вложения
+// с задачами, чтобы проверить пространство.
+const attachmentColumns = `a.id, a.task_id, a.uploader_id, a.filename, a.content_type, a.size, a.storage_key, a.created_at`
+
+func scanAttachment(row rowScanner) (*entity.Attachment, error) {
+	var a entity.Attachment
+	if err := row.Scan(&a.ID, &a.TaskID, &a.UploaderID, &a.Filename, &a.ContentType, &a.Size, &a.StorageKey, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Create добавляет вложение к задаче текущего пространства. Если задача
+// в нём не найдена, возвращается sql.ErrNoRows.
+func (r *AttachmentRepo) Create(ctx context.Context, a *entity.Attachment) (*entity.Attachment, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		INSERT INTO attachments (task_id, uploader_id, filename, content_type, size, storage_key, created_at)
+		SELECT t.id, $2, $3, $4, $5, $6, now()
+		FROM tasks t
+		WHERE t.id = $1 AND t.workspace_id = $7
+		RETURNING id, created_at
+	`
+	if err := r.db.QueryRowContext(ctx, query, a.TaskID, a.UploaderID, a.Filename, a.ContentType, a.Size, a.StorageKey, workspaceID).
+		Scan(&a.ID, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (r *AttachmentRepo) GetByID(ctx context.Context, id, taskID int64) (*entity.Attachment, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		SELECT ` + attachmentColumns + `
+		FROM attachments a
+		JOIN tasks t ON t.id = a.task_id
+		WHERE a.id = $1 AND a.task_id = $2 AND t.workspace_id = $3
+	`
+	return scanAttachment(r.db.QueryRowContext(ctx, query, id, taskID, workspaceID))
+}
+
+func (r *AttachmentRepo) ListByTask(ctx context.Context, taskID int64) ([]*entity.Attachment, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		SELECT ` + attachmentColumns + `
+		FROM attachments a
+		JOIN tasks t ON t.id = a.task_id
+		WHERE a.task_id = $1 AND t.workspace_id = $2
+		ORDER BY a.id
+	`
+	rows, err := r.db.QueryContext(ctx, query, taskID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := []*entity.Attachment{}
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+// Delete удаляет запись; файл попадёт в blob_deletions через триггер.
+func (r *AttachmentRepo) Delete(ctx context.Context, id, taskID int64) error {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	const query = `
+		DELETE FROM attachments a
+		USING tasks t
+		WHERE a.id = $1 AND a.task_id = $2 AND t.id = a.task_id AND t.workspace_id = $3
+	`
+	res, err := r.db.ExecContext(ctx, query, id, taskID, workspaceID)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// PendingBlobDeletions отдаёт по порядку ключи файлов после after, которые
+// осталось удалить из хранилища.
+func (r *AttachmentRepo) PendingBlobDeletions(ctx context.Context, after string, limit int) ([]string, error) {
+	const query = `SELECT storage_key FROM blob_deletions WHERE storage_key > $1 ORDER BY storage_key LIMIT $2`
+	rows, err := r.db.QueryContext(ctx, query, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *AttachmentRepo) ForgetBlobDeletion(ctx context.Context, key string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM blob_deletions WHERE storage_key = $1`, key)
+	return err
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"app/internal/entity"
+	"app/internal/tenant"
+	"context"
+	"database/sql"
+	"slices"
+)
+
+// Все изменения позиций идут в транзакции под блокировкой строки задачи
+// (SELECT … FOR UPDATE), поэтому параллельные перестановки одного чек-листа
+// выполняются по очереди и позиции остаются плотными 0..n-1.
+type ChecklistRepo struct {
+	db *sql.DB
+}
+
+func NewChecklistRepo(db *sql.DB) *ChecklistRepo {
+	return &ChecklistRepo{db: db}
+}
+
+const checklistColumns = `id, task_id, text, done, position, created_at, updated_at`
+
+// checklistItemColumns — те же колонки с псевдонимом c для запросов,
+// соединённых с задачами.
+const checklistItemColumns = `c.id, c.task_id, c.text, c.done, c.position, c.created_at, c.updated_at`
+
+func scanChecklistItem(row rowScanner) (*entity.ChecklistItem, error) {
+	var it entity.ChecklistItem
+	if err := row.Scan(&it.ID, &it.TaskID, &it.Text, &it.Done, &it.Position, &it.CreatedAt, &it.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &it, nil
+}
+
+// lockTask блокирует строку задачи до конца транзакции. Задача ищется только
+// в текущем пространстве, иначе возвращается sql.ErrNoRows.
+func lockTask(ctx context.Context, tx *sql.Tx, taskID int64) error {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	var id int64
+	return tx.QueryRowContext(ctx, `SELECT id FROM tasks WHERE id = $1 AND workspace_id = $2 FOR UPDATE`, taskID, workspaceID).Scan(&id)
+}
+
+func (r *ChecklistRepo) List(ctx context.Context, taskID int64) ([]*entity.ChecklistItem, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		SELECT ` + checklistItemColumns + `
+		FROM checklist_items c
+		JOIN tasks t ON t.id = c.task_id
+		WHERE c.task_id = $1 AND t.workspace_id = $2
+		ORDER BY c.position
+	`
+	rows, err := r.db.QueryContext(ctx, query, taskID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*entity.ChecklistItem{}
+	for rows.Next() {
+		it, err := scanChecklistItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+func (r *ChecklistRepo) GetByID(ctx context.Context, id, taskID int64) (*entity.ChecklistItem, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		SELECT ` + checklistItemColumns + `
+		FROM checklist_items c
+		JOIN tasks t ON t.id = c.task_id
+		WHERE c.id = $1 AND c.task_id = $2 AND t.workspace_id = $3
+	`
+	return scanChecklistItem(r.db.QueryRowContext(ctx, query, id, taskID, workspaceID))
+}
+
+// Add вставляет пункт на позицию position, сдвигая следующие; nil или позиция
+// за концом списка — добавить в конец.
+func (r *ChecklistRepo) Add(ctx context.Context, taskID int64, text string, position *int) (*entity.ChecklistItem, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockTask(ctx, tx, taskID); err != nil {
+		return nil, err
+	}
+	var n int
+	if err := tx.QueryRowContext(ctx, `SELECT count(*) FROM checklist_items WHERE task_id = $1`, taskID).Scan(&n); err != nil {
+		return nil, err
+	}
+	pos := n
+	if position != nil && *position < n {
+		pos = *position
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE checklist_items SET position = position + 1 WHERE task_id = $1 AND position >= $2`,
+		taskID, pos); err != nil {
+		return nil, err
+	}
+
+	const query = `
+		INSERT INTO checklist_items (task_id, text, position, created_at, updated_at)
+		VALUES ($1, $2, $3, now(), now())
+		RETURNING ` + checklistColumns
+	item, err := scanChecklistItem(tx.QueryRowContext(ctx, query, taskID, text, pos))
+	if err != nil {
+		return nil, err
+	}
+	return item, tx.Commit()
+}
+
+// Update меняет текст и отметку; позицию меняют только Add, Delete и Reorder.
+func (r *ChecklistRepo) Update(ctx context.Context, item *entity.ChecklistItem) (*entity.ChecklistItem, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		UPDATE checklist_items c
+		SET text = $1,
+		    done = $2,
+		    updated_at = now()
+		FROM tasks t
+		WHERE c.id = $3 AND c.task_id = $4 AND t.id = c.task_id AND t.workspace_id = $5
+		RETURNING ` + checklistItemColumns
+	return scanChecklistItem(r.db.QueryRowContext(ctx, query, item.Text, item.Done, item.ID, item.TaskID, workspaceID))
+}
+
+func (r *ChecklistRepo) Delete(ctx context.Context, id, taskID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockTask(ctx, tx, taskID); err != nil {
+		return err
+	}
+	var pos int
+	err = tx.QueryRowContext(ctx,
+		`DELETE FROM checklist_items WHERE id = $1 AND task_id = $2 RETURNING position`,
+		id, taskID).Scan(&pos)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE checklist_items SET position = position - 1 WHERE task_id = $1 AND position > $2`,
+		taskID, pos); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Reorder расставляет пункты в порядке ids. ids должны совпадать с текущим
+// набором пунктов: иначе клиент видел устаревший список (кто-то успел
+// добавить или удалить пункт), и Reorder возвращает false, ничего не меняя.
+func (r *ChecklistRepo) Reorder(ctx context.Context, taskID int64, ids []int64) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if err := lockTask(ctx, tx, taskID); err != nil {
+		return false, err
+	}
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM checklist_items WHERE task_id = $1`, taskID)
+	if err != nil {
+		return false, err
+	}
+	var current []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return false, err
+		}
+		current = append(current, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+
+	wanted := slices.Clone(ids)
+	slices.Sort(current)
+	slices.Sort(wanted)
+	if !slices.Equal(current, wanted) {
+		return false, nil
+	}
+
+	const query = `
+		UPDATE checklist_items c
+		SET position = o.ord - 1, updated_at = now()
+		FROM unnest($2::bigint[]) WITH ORDINALITY AS o(id, ord)
+		WHERE c.id = o.id AND c.task_id = $1 AND c.position <> o.ord - 1
+	`
+	if _, err := tx.ExecContext(ctx, query, taskID, ids); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"app/internal/entity"
+	"app/internal/tenant"
+	"context"
+	"database/sql"
+)
+
+type CommentRepo struct {
+	db *sql.DB
+}
+
+func NewCommentRepo(db *sql.DB) *CommentRepo {
+	return &CommentRepo{db: db}
+}
+
+// commentColumns рассчитаны на псевдоним c: запросы соединяют комментарии
+// с задачами, чтобы проверить пространство.
+const commentColumns = `c.id, c.task_id, c.author_id, c.body, c.created_at, c.edited_at`
+
+func scanComment(row rowScanner) (*entity.Comment, error) {
+	var c entity.Comment
+	if err := row.Scan(&c.ID, &c.TaskID, &c.AuthorID, &c.Body, &c.CreatedAt, &c.EditedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Create добавляет комментарий к задаче текущего пространства. Если задача
+// в нём не найдена, возвращается sql.ErrNoRows.
+func (r *CommentRepo) Create(ctx context.Context, comment *entity.Comment) (*entity.Comment, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		INSERT INTO comments (task_id, author_id, body, created_at)
+		SELECT t.id, $2, $3, now()
+		FROM tasks t
+		WHERE t.id = $1 AND t.workspace_id = $4
+		RETURNING id, created_at
+	`
+	if err := r.db.QueryRowContext(ctx, query, comment.TaskID, comment.AuthorID, comment.Body, workspaceID).
+		Scan(&comment.ID, &comment.CreatedAt); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (r *CommentRepo) Update(ctx context.Context, comment *entity.Comment) (*entity.Comment, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		UPDATE comments c
+		SET body = $1,
+		    edited_at = now()
+		FROM tasks t
+		WHERE c.id = $2 AND c.task_id = $3 AND t.id = c.task_id AND t.workspace_id = $4
+		RETURNING ` + commentColumns
+	return scanComment(r.db.QueryRowContext(ctx, query, comment.Body, comment.ID, comment.TaskID, workspaceID))
+}
+
+func (r *CommentRepo) Delete(ctx context.Context, id, taskID int64) error {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	const query = `
+		DELETE FROM comments c
+		USING tasks t
+		WHERE c.id = $1 AND c.task_id = $2 AND t.id = c.task_id AND t.workspace_id = $3
+	`
+	res, err := r.db.ExecContext(ctx, query, id, taskID, workspaceID)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *CommentRepo) GetByID(ctx context.Context, id, taskID int64) (*entity.Comment, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		SELECT ` + commentColumns + `
+		FROM comments c
+		JOIN tasks t ON t.id = c.task_id
+		WHERE c.id = $1 AND c.task_id = $2 AND t.workspace_id = $3
+	`
+	return scanComment(r.db.QueryRowContext(ctx, query, id, taskID, workspaceID))
+}
+
+// ListByTask отдаёт комментарии задачи в хронологическом порядке; задача
+// ищется только в текущем пространстве.
+func (r *CommentRepo) ListByTask(ctx context.Context, taskID int64) ([]*entity.Comment, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		SELECT ` + commentColumns + `
+		FROM comments c
+		JOIN tasks t ON t.id = c.task_id
+		WHERE c.task_id = $1 AND t.workspace_id = $2
+		ORDER BY c.id
+	`
+	rows, err := r.db.QueryContext(ctx, query, taskID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []*entity.Comment{}
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"app/internal/entity"
+	"app/internal/tenant"
+	"context"
+	"database/sql"
+)
+
+// Методы TaskRepo для зависимостей между задачами. Зависимости связывают
+// только задачи одного владельца в одном пространстве, поэтому рекурсия по
+// task_dependencies не фильтрует owner_id и workspace_id — они проверяются
+// у стартовой задачи.
+
+const upstreamCTE = `
+	up AS (
+		SELECT depends_on_id AS id FROM task_dependencies WHERE task_id = $1
+		UNION
+		SELECT d.depends_on_id FROM task_dependencies d JOIN up ON d.task_id = up.id
+	)
+`
+
+const downstreamCTE = `
+	down AS (
+		SELECT task_id AS id FROM task_dependencies WHERE depends_on_id = $1
+		UNION
+		SELECT d.task_id FROM task_dependencies d JOIN down ON d.depends_on_id = down.id
+	)
+`
+
+// AddDependency помечает задачу taskID заблокированной задачей dependsOnID.
+// Возвращает false, если dependsOnID уже (транзитивно) зависит от taskID:
+// новая связь замкнула бы цикл. Как и SetParent, работает под
+// advisory-блокировкой владельца.
+func (r *TaskRepo) AddDependency(ctx context.Context, taskID, dependsOnID, ownerID int64) (bool, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, ownerID); err != nil {
+		return false, err
+	}
+
+	var owned int
+	err = tx.QueryRowContext(ctx,
+		`SELECT count(*) FROM tasks WHERE id = ANY($1) AND owner_id = $2 AND workspace_id = $3`,
+		[]int64{taskID, dependsOnID}, ownerID, workspaceID).Scan(&owned)
+	if err != nil {
+		return false, err
+	}
+	if owned != 2 {
+		return false, sql.ErrNoRows
+	}
+
+	var cycle bool
+	const query = `WITH RECURSIVE ` + upstreamCTE + `SELECT EXISTS (SELECT 1 FROM up WHERE id = $2)`
+	if err := tx.QueryRowContext(ctx, query, dependsOnID, taskID).Scan(&cycle); err != nil {
+		return false, err
+	}
+	if cycle {
+		return false, nil
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO task_dependencies (task_id, depends_on_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, taskID, dependsOnID)
+	if err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (r *TaskRepo) RemoveDependency(ctx context.Context, taskID, dependsOnID, ownerID int64) error {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	const query = `
+		DELETE FROM task_dependencies d
+		USING tasks t
+		WHERE d.task_id = $1 AND d.depends_on_id = $2
+		  AND t.id = d.task_id AND t.owner_id = $3 AND t.workspace_id = $4
+	`
+	res, err := r.db.ExecContext(ctx, query, taskID, dependsOnID, ownerID, workspaceID)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// BlockersIn возвращает id прямых блокирующих задач, чей статус входит в statuses.
+func (r *TaskRepo) BlockersIn(ctx context.Context, id, ownerID int64, statuses []entity.TaskStatus) ([]int64, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		SELECT t.id
+		FROM task_dependencies d
+		JOIN tasks t ON t.id = d.depends_on_id
+		WHERE d.task_id = $1 AND t.owner_id = $2 AND t.workspace_id = $4 AND t.status = ANY($3)
+		ORDER BY t.id
+	`
+	rows, err := r.db.QueryContext(ctx, query, id, ownerID, statusStrings(statuses), workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var blocker int64
+		if err := rows.Scan(&blocker); err != nil {
+			return nil, err
+		}
+		ids = append(ids, blocker)
+	}
+	return ids, rows.Err()
+}
+
+// DependencyGraph собирает все задачи выше и ниже id по цепочке зависимостей
+// и рёбра между ними. Order не заполняется — его считает usecase.
+func (r *TaskRepo) DependencyGraph(ctx context.Context, id, ownerID int64) (*entity.DependencyGraph, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	graph := &entity.DependencyGraph{
+		TaskID:     id,
+		Upstream:   []int64{},
+		Downstream: []int64{},
+		Tasks:      []*entity.Task{},
+		Edges:      []entity.DependencyEdge{},
+	}
+
+	const sidesQuery = `WITH RECURSIVE ` + upstreamCTE + `, ` + downstreamCTE + `
+		SELECT id, true FROM up
+		UNION ALL
+		SELECT id, false FROM down
+		ORDER BY 1
+	`
+	rows, err := r.db.QueryContext(ctx, sidesQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int64{id}
+	for rows.Next() {
+		var (
+			node     int64
+			upstream bool
+		)
+		if err := rows.Scan(&node, &upstream); err != nil {
+			return nil, err
+		}
+		if upstream {
+			graph.Upstream = append(graph.Upstream, node)
+		} else {
+			graph.Downstream = append(graph.Downstream, node)
+		}
+		ids = append(ids, node)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	taskRows, err := r.db.QueryContext(ctx,
+		`SELECT `+taskColumns+` FROM tasks WHERE id = ANY($1) AND owner_id = $2 AND workspace_id = $3 ORDER BY id`,
+		ids, ownerID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer taskRows.Close()
+	for taskRows.Next() {
+		t, err := scanTask(taskRows)
+		if err != nil {
+			return nil, err
+		}
+		graph.Tasks = append(graph.Tasks, t)
+	}
+	if err := taskRows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.loadLabels(ctx, graph.Tasks); err != nil {
+		return nil, err
+	}
+
+	edgeRows, err := r.db.QueryContext(ctx, `
+		SELECT task_id, depends_on_id
+		FROM task_dependencies
+		WHERE task_id = ANY($1) AND depends_on_id = ANY($1)
+		ORDER BY task_id, depends_on_id
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer edgeRows.Close()
+	for edgeRows.Next() {
+		var e entity.DependencyEdge
+		if err := edgeRows.Scan(&e.TaskID, &e.DependsOnID); err != nil {
+			return nil, err
+		}
+		graph.Edges = append(graph.Edges, e)
+	}
+	return graph, edgeRows.Err()
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"app/internal/entity"
+	"app/internal/tenant"
+	"context"
+	"database/sql"
+)
+
+// LabelRepo работает с метками текущего рабочего пространства.
+type LabelRepo struct {
+	db *sql.DB
+}
+
+func NewLabelRepo(db *sql.DB) *LabelRepo {
+	return &LabelRepo{db: db}
+}
+
+const labelColumns = `id, owner_id, workspace_id, name, color, created_at, updated_at`
+
+func scanLabel(row rowScanner) (*entity.Label, error) {
+	var l entity.Label
+	if err := row.Scan(&l.ID, &l.OwnerID, &l.WorkspaceID, &l.Name, &l.Color, &l.CreatedAt, &l.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// Create заводит метку в текущем рабочем пространстве.
+func (r *LabelRepo) Create(ctx context.Context, label *entity.Label) (*entity.Label, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		INSERT INTO labels (owner_id, workspace_id, name, color, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, now(), now())
+		RETURNING id, created_at, updated_at
+	`
+	if err := r.db.QueryRowContext(ctx, query, label.OwnerID, workspaceID, label.Name, label.Color).
+		Scan(&label.ID, &label.CreatedAt, &label.UpdatedAt); err != nil {
+		return nil, err
+	}
+	label.WorkspaceID = workspaceID
+	return label, nil
+}
+
+func (r *LabelRepo) Update(ctx context.Context, label *entity.Label) (*entity.Label, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		UPDATE labels
+		SET name = $1,
+		    color = $2,
+		    updated_at = now()
+		WHERE id = $3 AND workspace_id = $4
+		RETURNING ` + labelColumns
+	return scanLabel(r.db.QueryRowContext(ctx, query, label.Name, label.Color, label.ID, workspaceID))
+}
+
+func (r *LabelRepo) Delete(ctx context.Context, id int64) error {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	const query = `DELETE FROM labels WHERE id = $1 AND workspace_id = $2`
+	res, err := r.db.ExecContext(ctx, query, id, workspaceID)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *LabelRepo) GetByID(ctx context.Context, id int64) (*entity.Label, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `SELECT ` + labelColumns + ` FROM labels WHERE id = $1 AND workspace_id = $2`
+	return scanLabel(r.db.QueryRowContext(ctx, query, id, workspaceID))
+}
+
+func (r *LabelRepo) GetByName(ctx context.Context, name string) (*entity.Label, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `SELECT ` + labelColumns + ` FROM labels WHERE workspace_id = $1 AND lower(name) = lower($2)`
+	return scanLabel(r.db.QueryRowContext(ctx, query, workspaceID, name))
+}
+
+func (r *LabelRepo) List(ctx context.Context) ([]*entity.Label, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `SELECT ` + labelColumns + ` FROM labels WHERE workspace_id = $1 ORDER BY lower(name)`
+	rows, err := r.db.QueryContext(ctx, query, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var labels []*entity.Label
+	for rows.Next() {
+		l, err := scanLabel(rows)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// Attach вешает метки на задачу. Если задача или хотя бы одна метка не
+// найдены в текущем пространстве, ничего не меняется и возвращается
+// sql.ErrNoRows.
+func (r *LabelRepo) Attach(ctx context.Context, taskID int64, labelIDs []int64) error {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var found int
+	const countQuery = `
+		SELECT count(*)
+		FROM labels l
+		JOIN tasks t ON t.id = $2 AND t.workspace_id = l.workspace_id
+		WHERE l.workspace_id = $1 AND l.id = ANY($3)
+	`
+	if err := tx.QueryRowContext(ctx, countQuery, workspaceID, taskID, labelIDs).Scan(&found); err != nil {
+		return err
+	}
+	if found != len(labelIDs) {
+		return sql.ErrNoRows
+	}
+
+	const query = `
+		INSERT INTO task_labels (task_id, label_id)
+		SELECT $1, unnest($2::bigint[])
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, query, taskID, labelIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *LabelRepo) Detach(ctx context.Context, taskID, labelID int64) error {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	const query = `
+		DELETE FROM task_labels tl
+		USING tasks t
+		WHERE tl.task_id = $1 AND tl.label_id = $2 AND t.id = tl.task_id AND t.workspace_id = $3
+	`
+	res, err := r.db.ExecContext(ctx, query, taskID, labelID, workspaceID)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
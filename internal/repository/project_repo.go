@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"app/internal/entity"
+	"app/internal/tenant"
+	"context"
+	"database/sql"
+)
+
+type ProjectRepo struct {
+	db *sql.DB
+}
+
+func NewProjectRepo(db *sql.DB) *ProjectRepo {
+	return &ProjectRepo{db: db}
+}
+
+// Create заводит проект в текущем рабочем пространстве.
+func (r *ProjectRepo) Create(ctx context.Context, project *entity.Project) (*entity.Project, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		INSERT INTO projects (owner_id, workspace_id, name, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, now(), now())
+		RETURNING id, created_at, updated_at
+	`
+	if err := r.db.QueryRowContext(ctx, query, project.OwnerID, workspaceID, project.Name, project.Description).
+		Scan(&project.ID, &project.CreatedAt, &project.UpdatedAt); err != nil {
+		return nil, err
+	}
+	project.WorkspaceID = workspaceID
+	return project, nil
+}
+
+func (r *ProjectRepo) Update(ctx context.Context, project *entity.Project) (*entity.Project, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		UPDATE projects
+		SET name = $1,
+		    description = $2,
+		    updated_at = now()
+		WHERE id = $3 AND owner_id = $4 AND workspace_id = $5
+		RETURNING workspace_id, created_at, updated_at
+	`
+	if err := r.db.QueryRowContext(ctx, query, project.Name, project.Description, project.ID, project.OwnerID, workspaceID).
+		Scan(&project.WorkspaceID, &project.CreatedAt, &project.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+func (r *ProjectRepo) Delete(ctx context.Context, id, ownerID int64) error {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	const query = `DELETE FROM projects WHERE id = $1 AND owner_id = $2 AND workspace_id = $3`
+	res, err := r.db.ExecContext(ctx, query, id, ownerID, workspaceID)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *ProjectRepo) GetByID(ctx context.Context, id, ownerID int64) (*entity.Project, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		SELECT id, owner_id, workspace_id, name, description, created_at, updated_at
+		FROM projects
+		WHERE id = $1 AND owner_id = $2 AND workspace_id = $3
+	`
+	var p entity.Project
+	if err := r.db.QueryRowContext(ctx, query, id, ownerID, workspaceID).Scan(
+		&p.ID, &p.OwnerID, &p.WorkspaceID, &p.Name, &p.Description, &p.CreatedAt, &p.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *ProjectRepo) List(ctx context.Context, ownerID int64) ([]*entity.Project, error) {
+	const query = `
+		SELECT id, owner_id, workspace_id, name, description, created_at, updated_at
+		FROM projects
+		WHERE owner_id = $1 AND workspace_id = $2
+		ORDER BY id DESC
+	`
+	return r.list(ctx, query, ownerID)
+}
+
+// ListShared возвращает чужие проекты пространства, к которым пользователю
+// выдан доступ.
+func (r *ProjectRepo) ListShared(ctx context.Context, userID int64) ([]*entity.Project, error) {
+	const query = `
+		SELECT p.id, p.owner_id, p.workspace_id, p.name, p.description, p.created_at, p.updated_at
+		FROM projects p
+		JOIN project_shares s ON s.project_id = p.id
+		WHERE s.user_id = $1 AND p.owner_id <> $1 AND p.workspace_id = $2
+		ORDER BY p.id DESC
+	`
+	return r.list(ctx, query, userID)
+}
+
+// list выполняет query с пользователем в $1 и текущим пространством в $2.
+func (r *ProjectRepo) list(ctx context.Context, query string, userID int64) ([]*entity.Project, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, query, userID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []*entity.Project
+	for rows.Next() {
+		var p entity.Project
+		if err := rows.Scan(&p.ID, &p.OwnerID, &p.WorkspaceID, &p.Name, &p.Description, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		projects = append(projects, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"app/internal/entity"
+	"context"
+	"database/sql"
+	"strings"
+)
+
+type RBACRepo struct {
+	db *sql.DB
+}
+
+func NewRBACRepo(db *sql.DB) *RBACRepo {
+	return &RBACRepo{db: db}
+}
+
+// Roles возвращает все системные роли с их разрешениями.
+func (r *RBACRepo) Roles(ctx context.Context) ([]*entity.AccountRole, error) {
+	const query = `
+		SELECT r.name, r.description, COALESCE(string_agg(rp.permission, ',' ORDER BY rp.permission), '')
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role = r.name
+		GROUP BY r.name, r.description
+		ORDER BY r.name
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []*entity.AccountRole{}
+	for rows.Next() {
+		var (
+			role  entity.AccountRole
+			perms string
+		)
+		if err := rows.Scan(&role.Name, &role.Description, &perms); err != nil {
+			return nil, err
+		}
+		role.Permissions = []entity.Permission{}
+		for _, p := range strings.Split(perms, ",") {
+			if p != "" {
+				role.Permissions = append(role.Permissions, entity.Permission(p))
+			}
+		}
+		roles = append(roles, &role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// UserRoles возвращает системные роли пользователя.
+func (r *RBACRepo) UserRoles(ctx context.Context, userID int64) ([]*entity.RoleGrant, error) {
+	const query = `
+		SELECT role, granted_by, created_at
+		FROM user_roles
+		WHERE user_id = $1
+		ORDER BY role
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	grants := []*entity.RoleGrant{}
+	for rows.Next() {
+		var g entity.RoleGrant
+		if err := rows.Scan(&g.Role, &g.GrantedBy, &g.CreatedAt); err != nil {
+			return nil, err
+		}
+		grants = append(grants, &g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
+// Grant выдаёт пользователю роль; повторная выдача ничего не меняет. Для
+// неизвестной роли или пользователя возвращается sql.ErrNoRows.
+func (r *RBACRepo) Grant(ctx context.Context, userID int64, role string, grantedBy *int64) error {
+	const query = `
+		INSERT INTO user_roles (user_id, role, granted_by, created_at)
+		SELECT u.id, r.name, $3, now()
+		FROM users u, roles r
+		WHERE u.id = $1 AND r.name = $2
+		ON CONFLICT (user_id, role) DO NOTHING
+		RETURNING 1
+	`
+	var inserted int
+	err := r.db.QueryRowContext(ctx, query, userID, role, grantedBy).Scan(&inserted)
+	if err == sql.ErrNoRows {
+		// ничего не вставлено: либо роль уже есть, либо нет роли или пользователя
+		var exists bool
+		if err := r.db.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM user_roles WHERE user_id = $1 AND role = $2)`,
+			userID, role).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
+		return sql.ErrNoRows
+	}
+	return err
+}
+
+// Revoke отзывает роль. Возвращает false, если это последний администратор:
+// без него управлять ролями было бы некому. Проверка и удаление идут под
+// advisory-блокировкой, чтобы два администратора не разжаловали друг друга
+// одновременно.
+func (r *RBACRepo) Revoke(ctx context.Context, userID int64, role string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('user_roles'))`); err != nil {
+		return false, err
+	}
+	if role == entity.RoleNameAdmin {
+		var others bool
+		if err := tx.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM user_roles WHERE role = $1 AND user_id <> $2)`,
+			role, userID).Scan(&others); err != nil {
+			return false, err
+		}
+		if !others {
+			return false, nil
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM user_roles WHERE user_id = $1 AND role = $2`, userID, role)
+	if err != nil {
+		return false, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return false, sql.ErrNoRows
+	}
+	return true, tx.Commit()
+}
+
+// grantDefaultRole выдаёт новому пользователю роль по умолчанию внутри
+// транзакции регистрации.
+func grantDefaultRole(ctx context.Context, tx *sql.Tx, userID int64) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO user_roles (user_id, role, created_at) VALUES ($1, $2, now())`,
+		userID, entity.RoleNameUser)
+	return err
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"app/internal/entity"
+	"context"
+)
+
+// spawnOccurrence снимает правило повторения с задачи done и создаёт
+// следующее повторение next. Правило снимается под блокировкой строки:
+// параллельное выполнение той же задачи дождётся коммита и уже не найдёт
+// правила — тогда возвращается nil, второе повторение не создаётся.
+// Поля правила в done сбрасываются в любом случае.
+func spawnOccurrence(ctx context.Context, db dbtx, done, next *entity.Task, workspaceID int64) (*entity.Task, error) {
+	res, err := db.ExecContext(ctx, `
+		UPDATE tasks
+		SET recurrence = '', recurrence_start = NULL, updated_at = now()
+		WHERE id = $1 AND owner_id = $2 AND workspace_id = $3 AND recurrence <> ''
+	`, done.ID, done.OwnerID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	done.Recurrence = ""
+	done.RecurrenceStart = nil
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, nil
+	}
+	if err := insertOccurrence(ctx, db, done, next, workspaceID); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// insertOccurrence создаёт следующее повторение next с метками, чек-листом,
+// доступами и относительными напоминаниями задачи done. Повторение остаётся
+// в пространстве done.
+func insertOccurrence(ctx context.Context, db dbtx, done, next *entity.Task, workspaceID int64) error {
+	const query = `
+		INSERT INTO tasks (owner_id, workspace_id, assignee_id, project_id, parent_id, title, description, status, priority, start_at, due_at, recurrence, recurrence_start, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, now(), now())
+		RETURNING id, created_at, updated_at
+	`
+	if err := db.QueryRowContext(ctx, query,
+		next.OwnerID,
+		workspaceID,
+		next.AssigneeID,
+		next.ProjectID,
+		next.ParentID,
+		next.Title,
+		next.Description,
+		next.Status,
+		next.Priority,
+		next.StartAt,
+		next.DueAt,
+		next.Recurrence,
+		next.RecurrenceStart,
+	).Scan(&next.ID, &next.CreatedAt, &next.UpdatedAt); err != nil {
+		return err
+	}
+	next.WorkspaceID = workspaceID
+
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO task_labels (task_id, label_id)
+		SELECT $1, label_id FROM task_labels WHERE task_id = $2
+	`, next.ID, done.ID)
+	if err != nil {
+		return err
+	}
+	// чек-лист переходит к следующему повторению неотмеченным
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO checklist_items (task_id, text, position)
+		SELECT $1, text, position FROM checklist_items WHERE task_id = $2
+	`, next.ID, done.ID)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO task_shares (task_id, user_id, role, created_at)
+		SELECT $1, user_id, role, now() FROM task_shares WHERE task_id = $2
+	`, next.ID, done.ID)
+	if err != nil {
+		return err
+	}
+	// напоминания «за N минут до срока» переходят к следующему повторению
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO reminders (task_id, offset_minutes)
+		SELECT $1, offset_minutes FROM reminders WHERE task_id = $2 AND offset_minutes IS NOT NULL
+	`, next.ID, done.ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
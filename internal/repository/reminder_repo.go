@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"app/internal/entity"
+	"app/internal/tenant"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type ReminderRepo struct {
+	db *sql.DB
+}
+
+func NewReminderRepo(db *sql.DB) *ReminderRepo {
+	return &ReminderRepo{db: db}
+}
+
+// fireAtExpr — момент срабатывания; требует alias r для reminders и t для tasks.
+const fireAtExpr = `COALESCE(r.remind_at, t.due_at - make_interval(mins => r.offset_minutes))`
+
+const reminderColumns = `r.id, r.task_id, r.offset_minutes, r.remind_at, ` + fireAtExpr + `, r.sent_at, r.attempts, r.last_error, r.created_at`
+
+func scanReminder(row rowScanner) (*entity.Reminder, error) {
+	var rem entity.Reminder
+	if err := row.Scan(
+		&rem.ID, &rem.TaskID, &rem.OffsetMinutes, &rem.RemindAt, &rem.FireAt,
+		&rem.SentAt, &rem.Attempts, &rem.LastError, &rem.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &rem, nil
+}
+
+func (r *ReminderRepo) Create(ctx context.Context, rem *entity.Reminder) (*entity.Reminder, error) {
+	const query = `
+		INSERT INTO reminders (task_id, offset_minutes, remind_at, created_at)
+		VALUES ($1, $2, $3, now())
+		RETURNING id
+	`
+	var id int64
+	if err := r.db.QueryRowContext(ctx, query, rem.TaskID, rem.OffsetMinutes, rem.RemindAt).Scan(&id); err != nil {
+		return nil, err
+	}
+	return r.get(ctx, id)
+}
+
+func (r *ReminderRepo) get(ctx context.Context, id int64) (*entity.Reminder, error) {
+	const query = `
+		SELECT ` + reminderColumns + `
+		FROM reminders r
+		JOIN tasks t ON t.id = r.task_id
+		WHERE r.id = $1
+	`
+	return scanReminder(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r *ReminderRepo) ListByTask(ctx context.Context, taskID, ownerID int64) ([]*entity.Reminder, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		SELECT ` + reminderColumns + `
+		FROM reminders r
+		JOIN tasks t ON t.id = r.task_id
+		WHERE r.task_id = $1 AND t.owner_id = $2 AND t.workspace_id = $3
+		ORDER BY ` + fireAtExpr + ` NULLS LAST, r.id
+	`
+	rows, err := r.db.QueryContext(ctx, query, taskID, ownerID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reminders := []*entity.Reminder{}
+	for rows.Next() {
+		rem, err := scanReminder(rows)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, rem)
+	}
+	return reminders, rows.Err()
+}
+
+func (r *ReminderRepo) Delete(ctx context.Context, id, taskID, ownerID int64) error {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	const query = `
+		DELETE FROM reminders r
+		USING tasks t
+		WHERE r.id = $1 AND r.task_id = $2
+		  AND t.id = r.task_id AND t.owner_id = $3 AND t.workspace_id = $4
+	`
+	res, err := r.db.ExecContext(ctx, query, id, taskID, ownerID, workspaceID)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Claim берёт до limit сработавших напоминаний и арендует их на lease.
+// FOR UPDATE SKIP LOCKED не даёт двум репликам взять одну строку, а аренда —
+// повторно взять её, пока доставка идёт. Если процесс упадёт, не отметив
+// доставку, аренда истечёт и напоминание будет взято снова (at-least-once).
+// Попытка засчитывается при захвате, поэтому maxAttempts ограничивает и
+// напоминания, на которых процесс падает.
+func (r *ReminderRepo) Claim(ctx context.Context, limit int, lease time.Duration, maxAttempts int) ([]entity.ReminderDelivery, error) {
+	const query = `
+		WITH due AS (
+			SELECT r.id
+			FROM reminders r
+			JOIN tasks t ON t.id = r.task_id
+			WHERE r.sent_at IS NULL
+			  AND r.attempts < $3
+			  AND (r.locked_until IS NULL OR r.locked_until <= now())
+			  AND ` + fireAtExpr + ` <= now()
+			ORDER BY r.id
+			LIMIT $1
+			FOR UPDATE OF r SKIP LOCKED
+		)
+		UPDATE reminders r
+		SET locked_until = now() + make_interval(secs => $2),
+		    attempts = r.attempts + 1
+		FROM due, tasks t
+		WHERE r.id = due.id AND t.id = r.task_id
+		RETURNING ` + reminderColumns + `
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Seconds(), maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		reminders []*entity.Reminder
+		taskIDs   []int64
+	)
+	for rows.Next() {
+		rem, err := scanReminder(rows)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, rem)
+		taskIDs = append(taskIDs, rem.TaskID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(reminders) == 0 {
+		return nil, nil
+	}
+
+	taskRows, err := r.db.QueryContext(ctx, `SELECT `+taskColumns+` FROM tasks WHERE id = ANY($1)`, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer taskRows.Close()
+	tasks := make(map[int64]*entity.Task, len(taskIDs))
+	for taskRows.Next() {
+		t, err := scanTask(taskRows)
+		if err != nil {
+			return nil, err
+		}
+		tasks[t.ID] = t
+	}
+	if err := taskRows.Err(); err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]entity.ReminderDelivery, 0, len(reminders))
+	for _, rem := range reminders {
+		if t, ok := tasks[rem.TaskID]; ok {
+			deliveries = append(deliveries, entity.ReminderDelivery{Reminder: rem, Task: t})
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *ReminderRepo) MarkSent(ctx context.Context, id int64) error {
+	const query = `
+		UPDATE reminders
+		SET sent_at = now(), locked_until = NULL, last_error = ''
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// MarkFailed запоминает ошибку доставки; следующая попытка — не раньше retryAt.
+func (r *ReminderRepo) MarkFailed(ctx context.Context, id int64, reason string, retryAt time.Time) error {
+	const query = `
+		UPDATE reminders
+		SET last_error = $2, locked_until = $3
+		WHERE id = $1
+	`
+	_, err := r.db.ExecContext(ctx, query, id, reason, retryAt)
+	return err
+}
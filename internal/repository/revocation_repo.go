@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"app/internal/entity"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type RevocationRepo struct {
+	db *sql.DB
+}
+
+func NewRevocationRepo(db *sql.DB) *RevocationRepo {
+	return &RevocationRepo{db: db}
+}
+
+// Revoke запоминает отозванный JWT до момента, когда он истечёт сам.
+func (r *RevocationRepo) Revoke(ctx context.Context, token entity.RevokedToken) error {
+	const query = `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at, revoked_at)
+		VALUES ($1, $2, $3, now())
+		ON CONFLICT (jti) DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, token.ID, token.UserID, token.ExpiresAt)
+	return err
+}
+
+// RevokedSince возвращает ещё не истёкшие токены, отозванные начиная с since.
+func (r *RevocationRepo) RevokedSince(ctx context.Context, since time.Time) ([]entity.RevokedToken, error) {
+	const query = `
+		SELECT jti, user_id, expires_at
+		FROM revoked_tokens
+		WHERE revoked_at >= $1 AND expires_at > now()
+	`
+	rows, err := r.db.QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []entity.RevokedToken
+	for rows.Next() {
+		var t entity.RevokedToken
+		if err := rows.Scan(&t.ID, &t.UserID, &t.ExpiresAt); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// DeleteExpired забывает токены, которые уже истекли сами.
+func (r *RevocationRepo) DeleteExpired(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM revoked_tokens WHERE expires_at <= now()`)
+	return err
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"app/internal/entity"
+	"context"
+	"database/sql"
+	"time"
+)
+
+// SessionRepo хранит сессии (входы пользователя) и хэши их refresh-токенов.
+type SessionRepo struct {
+	db *sql.DB
+}
+
+func NewSessionRepo(db *sql.DB) *SessionRepo {
+	return &SessionRepo{db: db}
+}
+
+// Create заводит сессию вместе с её первым refresh-токеном.
+func (r *SessionRepo) Create(ctx context.Context, session *entity.Session, tokenHash string, expiresAt time.Time) (*entity.Session, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO sessions (user_id, user_agent, ip, created_at, last_seen_at)
+		VALUES ($1, $2, $3, now(), now())
+		RETURNING id, created_at, last_seen_at
+	`, session.UserID, session.UserAgent, session.IP).Scan(&session.ID, &session.CreatedAt, &session.LastSeenAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := createRefreshToken(ctx, tx, session, tokenHash, expiresAt); err != nil {
+		return nil, err
+	}
+	return session, tx.Commit()
+}
+
+func createRefreshToken(ctx context.Context, tx *sql.Tx, session *entity.Session, tokenHash string, expiresAt time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO refresh_tokens (user_id, session_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, now())
+	`, session.UserID, session.ID, tokenHash, expiresAt)
+	return err
+}
+
+// Rotate меняет действующий refresh-токен на новый той же сессии и
+// возвращает сессию. Уже использованный токен означает, что его
+// перехватили: сессия отзывается целиком, и возвращается false. Для
+// неизвестного, истёкшего или отозванного токена — sql.ErrNoRows.
+func (r *SessionRepo) Rotate(ctx context.Context, tokenHash, newHash string, expiresAt time.Time) (*entity.Session, bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	var (
+		id                int64
+		expired           bool
+		usedAt, revokedAt sql.NullTime
+		s                 entity.Session
+	)
+	err = tx.QueryRowContext(ctx, `
+		SELECT t.id, t.expires_at <= now(), t.used_at, t.revoked_at,
+		       s.id, s.user_id, s.user_agent, s.ip, s.created_at, s.last_seen_at
+		FROM refresh_tokens t
+		JOIN sessions s ON s.id = t.session_id
+		WHERE t.token_hash = $1
+		FOR UPDATE OF t
+	`, tokenHash).Scan(&id, &expired, &usedAt, &revokedAt,
+		&s.ID, &s.UserID, &s.UserAgent, &s.IP, &s.CreatedAt, &s.LastSeenAt)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch {
+	case revokedAt.Valid:
+		return nil, false, sql.ErrNoRows
+	case usedAt.Valid:
+		if err := revokeSession(ctx, tx, s.ID); err != nil {
+			return nil, false, err
+		}
+		return &s, false, tx.Commit()
+	case expired:
+		return nil, false, sql.ErrNoRows
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE refresh_tokens SET used_at = now() WHERE id = $1`, id); err != nil {
+		return nil, false, err
+	}
+	if err := createRefreshToken(ctx, tx, &s, newHash, expiresAt); err != nil {
+		return nil, false, err
+	}
+	return &s, true, tx.Commit()
+}
+
+// ListActive возвращает неотозванные сессии пользователя, у которых есть
+// действующий refresh-токен, — свежие первыми.
+func (r *SessionRepo) ListActive(ctx context.Context, userID int64) ([]*entity.Session, error) {
+	const query = `
+		SELECT s.id, s.user_id, s.user_agent, s.ip, s.created_at, s.last_seen_at
+		FROM sessions s
+		WHERE s.user_id = $1 AND s.revoked_at IS NULL
+		  AND EXISTS (
+			SELECT 1 FROM refresh_tokens t
+			WHERE t.session_id = s.id
+			  AND t.used_at IS NULL AND t.revoked_at IS NULL AND t.expires_at > now()
+		  )
+		ORDER BY s.last_seen_at DESC, s.id DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*entity.Session{}
+	for rows.Next() {
+		var s entity.Session
+		if err := rows.Scan(&s.ID, &s.UserID, &s.UserAgent, &s.IP, &s.CreatedAt, &s.LastSeenAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Revoke отзывает сессию пользователя вместе с её refresh-токенами. Для
+// чужой, неизвестной или уже отозванной сессии — sql.ErrNoRows.
+func (r *SessionRepo) Revoke(ctx context.Context, id, userID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var sessionID int64
+	err = tx.QueryRowContext(ctx, `
+		SELECT id FROM sessions
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+		FOR UPDATE
+	`, id, userID).Scan(&sessionID)
+	if err != nil {
+		return err
+	}
+	if err := revokeSession(ctx, tx, sessionID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func revokeSession(ctx context.Context, tx *sql.Tx, id int64) error {
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE sessions SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL`, id); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = now() WHERE session_id = $1 AND revoked_at IS NULL`, id)
+	return err
+}
+
+// revokeSessions отзывает все сессии пользователя и их refresh-токены.
+func revokeSessions(ctx context.Context, tx *sql.Tx, userID int64) error {
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE sessions SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`, userID); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`, userID)
+	return err
+}
+
+// RevokedSince возвращает id и время отзыва сессий, отозванных начиная с since.
+func (r *SessionRepo) RevokedSince(ctx context.Context, since time.Time) ([]*entity.Session, error) {
+	const query = `
+		SELECT id, user_id, revoked_at
+		FROM sessions
+		WHERE revoked_at >= $1
+	`
+	rows, err := r.db.QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*entity.Session
+	for rows.Next() {
+		var s entity.Session
+		if err := rows.Scan(&s.ID, &s.UserID, &s.RevokedAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// SetLastSeen записывает накопленные отметки активности одним запросом.
+// Более раннее время не затирает более позднее.
+func (r *SessionRepo) SetLastSeen(ctx context.Context, seen map[int64]time.Time) error {
+	if len(seen) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(seen))
+	times := make([]time.Time, 0, len(seen))
+	for id, t := range seen {
+		ids = append(ids, id)
+		times = append(times, t)
+	}
+	const query = `
+		UPDATE sessions s
+		SET last_seen_at = v.seen_at
+		FROM unnest($1::bigint[], $2::timestamptz[]) AS v(id, seen_at)
+		WHERE s.id = v.id AND s.last_seen_at < v.seen_at
+	`
+	_, err := r.db.ExecContext(ctx, query, ids, times)
+	return err
+}
+
+// DeleteExpired удаляет сессии, в которых не осталось действующих
+// refresh-токенов (их токены удаляются каскадно). Отозванные сессии
+// хранятся до revokedBefore: пока живы выданные в них JWT, отзыв должен
+// оставаться виден всем экземплярам сервиса.
+func (r *SessionRepo) DeleteExpired(ctx context.Context, revokedBefore time.Time) error {
+	const query = `
+		DELETE FROM sessions s
+		WHERE (s.revoked_at IS NULL OR s.revoked_at < $1)
+		  AND NOT EXISTS (
+			SELECT 1 FROM refresh_tokens t
+			WHERE t.session_id = s.id
+			  AND t.used_at IS NULL AND t.revoked_at IS NULL AND t.expires_at > now()
+		  )
+	`
+	_, err := r.db.ExecContext(ctx, query, revokedBefore)
+	return err
+}
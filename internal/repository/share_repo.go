@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"app/internal/entity"
+	"app/internal/tenant"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type ShareRepo struct {
+	db *sql.DB
+}
+
+func NewShareRepo(db *sql.DB) *ShareRepo {
+	return &ShareRepo{db: db}
+}
+
+type shareTable struct {
+	table, column string
+}
+
+var shareTables = map[entity.ShareTarget]shareTable{
+	entity.ShareTask:    {table: "task_shares", column: "task_id"},
+	entity.ShareProject: {table: "project_shares", column: "project_id"},
+}
+
+func tableFor(target entity.ShareTarget) (shareTable, error) {
+	t, ok := shareTables[target]
+	if !ok {
+		return t, fmt.Errorf("unknown share target %q", target)
+	}
+	return t, nil
+}
+
+// roleRankExpr упорядочивает роли так же, как entity.Role.Allows.
+const roleRankExpr = `array_position(ARRAY['viewer', 'editor', 'owner'], s.role)`
+
+// workspaceAdminExpr — пользователь $2 администрирует пространство $3;
+// администратору доступно всё пространство с правами владельца.
+const workspaceAdminExpr = `EXISTS (
+	SELECT 1 FROM workspace_members m
+	WHERE m.workspace_id = $3 AND m.user_id = $2 AND m.role = 'admin'
+)`
+
+// TaskRole возвращает роль пользователя в задаче и её владельца. Роль —
+// наибольшая из выданных на саму задачу и на её проект. Если задачи нет
+// в текущем пространстве или доступа к ней нет, возвращается sql.ErrNoRows.
+func (r *ShareRepo) TaskRole(ctx context.Context, taskID, userID int64) (entity.Role, int64, error) {
+	const query = `
+		SELECT t.owner_id,
+		       CASE WHEN t.owner_id = $2 OR ` + workspaceAdminExpr + ` THEN 'owner' ELSE (
+		           SELECT s.role FROM (
+		               SELECT role FROM task_shares WHERE task_id = t.id AND user_id = $2
+		               UNION ALL
+		               SELECT role FROM project_shares WHERE project_id = t.project_id AND user_id = $2
+		           ) s
+		           ORDER BY ` + roleRankExpr + ` DESC
+		           LIMIT 1
+		       ) END
+		FROM tasks t
+		WHERE t.id = $1 AND t.workspace_id = $3
+	`
+	return r.role(ctx, query, taskID, userID)
+}
+
+// ProjectRole — то же для проекта.
+func (r *ShareRepo) ProjectRole(ctx context.Context, projectID, userID int64) (entity.Role, int64, error) {
+	const query = `
+		SELECT p.owner_id,
+		       CASE WHEN p.owner_id = $2 OR ` + workspaceAdminExpr + ` THEN 'owner' ELSE (
+		           SELECT role FROM project_shares WHERE project_id = p.id AND user_id = $2
+		       ) END
+		FROM projects p
+		WHERE p.id = $1 AND p.workspace_id = $3
+	`
+	return r.role(ctx, query, projectID, userID)
+}
+
+func (r *ShareRepo) role(ctx context.Context, query string, id, userID int64) (entity.Role, int64, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	var (
+		ownerID int64
+		role    sql.NullString
+	)
+	if err := r.db.QueryRowContext(ctx, query, id, userID, workspaceID).Scan(&ownerID, &role); err != nil {
+		return "", 0, err
+	}
+	if !role.Valid {
+		return "", 0, sql.ErrNoRows
+	}
+	return entity.Role(role.String), ownerID, nil
+}
+
+// Grant выдаёт пользователю доступ или меняет его роль.
+func (r *ShareRepo) Grant(ctx context.Context, target entity.ShareTarget, id, userID int64, role entity.Role) error {
+	t, err := tableFor(target)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO ` + t.table + ` (` + t.column + `, user_id, role, created_at)
+		VALUES ($1, $2, $3, now())
+		ON CONFLICT (` + t.column + `, user_id) DO UPDATE SET role = EXCLUDED.role
+	`
+	_, err = r.db.ExecContext(ctx, query, id, userID, role)
+	return err
+}
+
+func (r *ShareRepo) Revoke(ctx context.Context, target entity.ShareTarget, id, userID int64) error {
+	t, err := tableFor(target)
+	if err != nil {
+		return err
+	}
+	query := `DELETE FROM ` + t.table + ` WHERE ` + t.column + ` = $1 AND user_id = $2`
+	res, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *ShareRepo) List(ctx context.Context, target entity.ShareTarget, id int64) ([]*entity.Share, error) {
+	t, err := tableFor(target)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT s.user_id, u.email, s.role, s.created_at
+		FROM ` + t.table + ` s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.` + t.column + ` = $1
+		ORDER BY s.created_at, s.user_id
+	`
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shares := []*entity.Share{}
+	for rows.Next() {
+		var s entity.Share
+		if err := rows.Scan(&s.UserID, &s.Email, &s.Role, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		shares = append(shares, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"app/internal/entity"
+	"app/internal/tenant"
+	"context"
+	"database/sql"
+)
+
+// Методы TaskRepo для дерева подзадач. Везде используется UNION, а не
+// UNION ALL: даже если в данных окажется цикл, рекурсия остановится.
+
+// descendantsCTE ждёт задачу в $1, владельца в $2 и рабочее пространство в $3.
+const descendantsCTE = `
+	WITH RECURSIVE sub AS (
+		SELECT id FROM tasks WHERE parent_id = $1 AND owner_id = $2 AND workspace_id = $3
+		UNION
+		SELECT t.id FROM tasks t JOIN sub ON t.parent_id = sub.id
+	)
+`
+
+// SetParent перевешивает задачу под нового родителя (nil — сделать корневой).
+// Возвращает false, если parentID — сама задача или её потомок: такой перенос
+// образовал бы цикл. Проверка и запись идут под advisory-блокировкой владельца,
+// чтобы два встречных переноса не замкнули цикл одновременно.
+func (r *TaskRepo) SetParent(ctx context.Context, id, ownerID int64, parentID *int64) (bool, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, ownerID); err != nil {
+		return false, err
+	}
+
+	if parentID != nil {
+		var cycle bool
+		const query = descendantsCTE + `SELECT $4 = $1 OR EXISTS (SELECT 1 FROM sub WHERE id = $4)`
+		if err := tx.QueryRowContext(ctx, query, id, ownerID, workspaceID, *parentID).Scan(&cycle); err != nil {
+			return false, err
+		}
+		if cycle {
+			return false, nil
+		}
+	}
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE tasks SET parent_id = $1, updated_at = now() WHERE id = $2 AND owner_id = $3 AND workspace_id = $4`,
+		parentID, id, ownerID, workspaceID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return false, sql.ErrNoRows
+	}
+	return true, tx.Commit()
+}
+
+// Descendants возвращает всех потомков задачи (без неё самой).
+func (r *TaskRepo) Descendants(ctx context.Context, id, ownerID int64) ([]*entity.Task, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = descendantsCTE + `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE id IN (SELECT id FROM sub)
+		ORDER BY id
+	`
+	rows, err := r.db.QueryContext(ctx, query, id, ownerID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []*entity.Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.loadLabels(ctx, tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// ChildStatusCounts считает прямые подзадачи каждого из родителей по статусам.
+func (r *TaskRepo) ChildStatusCounts(ctx context.Context, parentIDs []int64) (map[int64]map[entity.TaskStatus]int, error) {
+	counts := make(map[int64]map[entity.TaskStatus]int)
+	if len(parentIDs) == 0 {
+		return counts, nil
+	}
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		SELECT parent_id, status, count(*)
+		FROM tasks
+		WHERE parent_id = ANY($1) AND workspace_id = $2
+		GROUP BY parent_id, status
+	`
+	rows, err := r.db.QueryContext(ctx, query, parentIDs, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			parentID int64
+			status   entity.TaskStatus
+			n        int
+		)
+		if err := rows.Scan(&parentID, &status, &n); err != nil {
+			return nil, err
+		}
+		if counts[parentID] == nil {
+			counts[parentID] = make(map[entity.TaskStatus]int)
+		}
+		counts[parentID][status] = n
+	}
+	return counts, rows.Err()
+}
+
+// HasDescendantsIn сообщает, есть ли у задачи потомки в одном из статусов;
+// пустой список статусов — любые потомки.
+func (r *TaskRepo) HasDescendantsIn(ctx context.Context, id, ownerID int64, statuses []entity.TaskStatus) (bool, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return false, err
+	}
+	query := descendantsCTE + `SELECT EXISTS (SELECT 1 FROM tasks WHERE id IN (SELECT id FROM sub)`
+	args := []any{id, ownerID, workspaceID}
+	if len(statuses) > 0 {
+		query += ` AND status = ANY($4)`
+		args = append(args, statusStrings(statuses))
+	}
+	query += `)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// DeleteTree удаляет задачу вместе со всеми потомками.
+func (r *TaskRepo) DeleteTree(ctx context.Context, id, ownerID int64) error {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	const query = descendantsCTE + `
+		DELETE FROM tasks
+		WHERE owner_id = $2 AND workspace_id = $3 AND (id = $1 OR id IN (SELECT id FROM sub))
+	`
+	res, err := r.db.ExecContext(ctx, query, id, ownerID, workspaceID)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func statusStrings(statuses []entity.TaskStatus) []string {
+	out := make([]string, len(statuses))
+	for i, s := range statuses {
+		out[i] = string(s)
+	}
+	return out
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"app/internal/entity"
+	"fmt"
+	"strings"
+)
+
+// statusCategoryExpr — категория статуса задачи по workflow её владельца:
+// встроенные состояния у всех одинаковы, пользовательские берутся из
+// workflow_states владельца. В списках со scope=shared/all у задач разные
+// владельцы, поэтому «открыта» нельзя свести к списку статусов одного из них.
+var statusCategoryExpr = func() string {
+	var b strings.Builder
+	b.WriteString("(CASE tasks.status")
+	for _, s := range entity.BuiltinStates() {
+		fmt.Fprintf(&b, " WHEN '%s' THEN '%s'", s.Key, s.Category)
+	}
+	b.WriteString(" ELSE (SELECT ws.category FROM workflow_states ws WHERE ws.owner_id = tasks.owner_id AND ws.key = tasks.status) END)")
+	return b.String()
+}()
+
+// filterColumns — белый список колонок, доступных в выражении фильтра.
+var filterColumns = map[entity.FilterField]string{
+	entity.FilterID:             "id",
+	entity.FilterProjectID:      "project_id",
+	entity.FilterParentID:       "parent_id",
+	entity.FilterTitle:          "title",
+	entity.FilterDescription:    "description",
+	entity.FilterStatus:         "status",
+	entity.FilterStatusCategory: statusCategoryExpr,
+	entity.FilterPriority:       "priority",
+	entity.FilterStartAt:        "start_at",
+	entity.FilterDueAt:          "due_at",
+	entity.FilterCreatedAt:      "created_at",
+	entity.FilterUpdatedAt:      "updated_at",
+}
+
+// compileFilter переводит дерево фильтра в параметризованное SQL-условие.
+func compileFilter(node entity.FilterNode, args *sqlArgs) (string, error) {
+	switch n := node.(type) {
+	case entity.FilterAnd:
+		return compileBinary(n.Left, n.Right, "AND", args)
+	case entity.FilterOr:
+		return compileBinary(n.Left, n.Right, "OR", args)
+	case entity.FilterNot:
+		expr, err := compileFilter(n.Expr, args)
+		if err != nil {
+			return "", err
+		}
+		// NULL внутри (например, due_at у задачи без срока) считается
+		// несовпадением, иначе NOT дал бы NULL и задача пропала бы из выборки —
+		// так же, как IS DISTINCT FROM для «!=».
+		return "NOT COALESCE(" + expr + ", false)", nil
+	case entity.FilterCond:
+		return compileCond(n, args)
+	}
+	return "", fmt.Errorf("filter: unsupported node %T", node)
+}
+
+func compileBinary(left, right entity.FilterNode, op string, args *sqlArgs) (string, error) {
+	l, err := compileFilter(left, args)
+	if err != nil {
+		return "", err
+	}
+	r, err := compileFilter(right, args)
+	if err != nil {
+		return "", err
+	}
+	return "(" + l + " " + op + " " + r + ")", nil
+}
+
+// hasLabel — условие «у задачи есть метка с именем из параметра param».
+func hasLabel(param string) string {
+	return `EXISTS (
+		SELECT 1 FROM task_labels tl
+		JOIN labels l ON l.id = tl.label_id
+		WHERE tl.task_id = tasks.id AND lower(l.name) = lower(` + param + `))`
+}
+
+func compileCond(c entity.FilterCond, args *sqlArgs) (string, error) {
+	if c.Field == entity.FilterLabel {
+		switch c.Op {
+		case entity.OpEq:
+			return hasLabel(args.add(c.Value)), nil
+		case entity.OpNe:
+			return "NOT " + hasLabel(args.add(c.Value)), nil
+		}
+		return "", fmt.Errorf("filter: unsupported operator %q for label", c.Op)
+	}
+
+	col, ok := filterColumns[c.Field]
+	if !ok {
+		return "", fmt.Errorf("filter: unknown field %q", c.Field)
+	}
+
+	switch c.Op {
+	case entity.OpEq, entity.OpLt, entity.OpLe, entity.OpGt, entity.OpGe:
+		return "(" + col + " " + string(c.Op) + " " + args.add(c.Value) + ")", nil
+	case entity.OpNe:
+		return "(" + col + " IS DISTINCT FROM " + args.add(c.Value) + ")", nil
+	case entity.OpContains:
+		s, ok := c.Value.(string)
+		if !ok {
+			return "", fmt.Errorf("filter: %q expects text", c.Field)
+		}
+		return "(" + col + " ILIKE '%' || " + args.add(escapeLike(s)) + " || '%')", nil
+	case entity.OpIn:
+		statuses, ok := c.Value.([]entity.TaskStatus)
+		if !ok {
+			return "", fmt.Errorf("filter: %q expects a list", c.Field)
+		}
+		values := make([]string, len(statuses))
+		for i, s := range statuses {
+			values[i] = string(s)
+		}
+		return "(" + col + " = ANY(" + args.add(values) + "))", nil
+	}
+	return "", fmt.Errorf("filter: unsupported operator %q", c.Op)
+}
+
+// escapeLike экранирует спецсимволы LIKE, чтобы «%» и «_» искались буквально.
+func escapeLike(s string) string {
+	out := make([]rune, 0, len(s))
+	for _, r := range s {
+		if r == '%' || r == '_' || r == '\\' {
+			out = append(out, '\\')
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
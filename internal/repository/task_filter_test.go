@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"app/internal/entity"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompileFilter(t *testing.T) {
+	jan1 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	jan2 := jan1.AddDate(0, 0, 1)
+	dueOnJan1 := entity.FilterAnd{
+		Left:  entity.FilterCond{Field: entity.FilterDueAt, Op: entity.OpGe, Value: jan1},
+		Right: entity.FilterCond{Field: entity.FilterDueAt, Op: entity.OpLt, Value: jan2},
+	}
+
+	tests := []struct {
+		name     string
+		node     entity.FilterNode
+		wantSQL  string
+		wantArgs sqlArgs
+	}{
+		{
+			name:     "eq",
+			node:     entity.FilterCond{Field: entity.FilterID, Op: entity.OpEq, Value: int64(5)},
+			wantSQL:  "(id = $1)",
+			wantArgs: sqlArgs{int64(5)},
+		},
+		{
+			name:     "ne keeps NULL",
+			node:     entity.FilterCond{Field: entity.FilterProjectID, Op: entity.OpNe, Value: int64(7)},
+			wantSQL:  "(project_id IS DISTINCT FROM $1)",
+			wantArgs: sqlArgs{int64(7)},
+		},
+		{
+			name:     "contains escapes LIKE",
+			node:     entity.FilterCond{Field: entity.FilterTitle, Op: entity.OpContains, Value: `50%_a\b`},
+			wantSQL:  "(title ILIKE '%' || $1 || '%')",
+			wantArgs: sqlArgs{`50\%\_a\\b`},
+		},
+		{
+			name:     "in",
+			node:     entity.FilterCond{Field: entity.FilterStatus, Op: entity.OpIn, Value: []entity.TaskStatus{"todo", "review"}},
+			wantSQL:  "(status = ANY($1))",
+			wantArgs: sqlArgs{[]string{"todo", "review"}},
+		},
+		{
+			name:     "status category",
+			node:     entity.FilterCond{Field: entity.FilterStatusCategory, Op: entity.OpNe, Value: entity.CategoryOpen},
+			wantSQL:  "(" + statusCategoryExpr + " IS DISTINCT FROM $1)",
+			wantArgs: sqlArgs{entity.CategoryOpen},
+		},
+		{
+			name: "and or",
+			node: entity.FilterOr{
+				Left: entity.FilterCond{Field: entity.FilterID, Op: entity.OpEq, Value: int64(1)},
+				Right: entity.FilterAnd{
+					Left:  entity.FilterCond{Field: entity.FilterPriority, Op: entity.OpGe, Value: entity.PriorityHigh},
+					Right: entity.FilterCond{Field: entity.FilterParentID, Op: entity.OpEq, Value: int64(3)},
+				},
+			},
+			wantSQL:  "((id = $1) OR ((priority >= $2) AND (parent_id = $3)))",
+			wantArgs: sqlArgs{int64(1), entity.PriorityHigh, int64(3)},
+		},
+		{
+			// due_at!=2026-01-01 должен оставлять задачи без срока, как и «!=» по колонке
+			name:     "not date range",
+			node:     entity.FilterNot{Expr: dueOnJan1},
+			wantSQL:  "NOT COALESCE(((due_at >= $1) AND (due_at < $2)), false)",
+			wantArgs: sqlArgs{jan1, jan2},
+		},
+		{
+			name:     "double not",
+			node:     entity.FilterNot{Expr: entity.FilterNot{Expr: entity.FilterCond{Field: entity.FilterDueAt, Op: entity.OpLt, Value: jan1}}},
+			wantSQL:  "NOT COALESCE(NOT COALESCE((due_at < $1), false), false)",
+			wantArgs: sqlArgs{jan1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args sqlArgs
+			got, err := compileFilter(tt.node, &args)
+			if err != nil {
+				t.Fatalf("compileFilter: %v", err)
+			}
+			if got != tt.wantSQL {
+				t.Errorf("SQL\n got  %s\n want %s", got, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCompileFilterLabel(t *testing.T) {
+	var args sqlArgs
+	got, err := compileFilter(entity.FilterNot{Expr: entity.FilterCond{Field: entity.FilterLabel, Op: entity.OpNe, Value: "backend"}}, &args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(got, "NOT COALESCE(NOT EXISTS (") || !strings.Contains(got, "lower(l.name) = lower($1)") {
+		t.Errorf("SQL %s", got)
+	}
+	if !reflect.DeepEqual(args, sqlArgs{"backend"}) {
+		t.Errorf("args %#v", args)
+	}
+}
+
+func TestCompileFilterRejects(t *testing.T) {
+	nodes := map[string]entity.FilterNode{
+		"unknown field":   entity.FilterCond{Field: "owner_id", Op: entity.OpEq, Value: int64(1)},
+		"label compare":   entity.FilterCond{Field: entity.FilterLabel, Op: entity.OpLt, Value: "x"},
+		"contains number": entity.FilterCond{Field: entity.FilterTitle, Op: entity.OpContains, Value: int64(1)},
+		"in without list": entity.FilterCond{Field: entity.FilterStatus, Op: entity.OpIn, Value: "todo"},
+		"unknown op":      entity.FilterCond{Field: entity.FilterID, Op: "~~", Value: int64(1)},
+		"nil node":        nil,
+	}
+	for name, node := range nodes {
+		t.Run(name, func(t *testing.T) {
+			var args sqlArgs
+			if sql, err := compileFilter(node, &args); err == nil {
+				t.Fatalf("expected error, got %s", sql)
+			}
+		})
+	}
+}
+
+// Категорию статуса надо брать из workflow владельца задачи, а не того,
+// кто смотрит список.
+func TestStatusCategoryExpr(t *testing.T) {
+	for _, want := range []string{
+		"WHEN 'todo' THEN 'open'",
+		"WHEN 'blocked' THEN 'open'",
+		"WHEN 'done' THEN 'done'",
+		"WHEN 'cancelled' THEN 'cancelled'",
+		"ws.owner_id = tasks.owner_id AND ws.key = tasks.status",
+	} {
+		if !strings.Contains(statusCategoryExpr, want) {
+			t.Errorf("statusCategoryExpr lacks %q:\n%s", want, statusCategoryExpr)
+		}
+	}
+}
@@ -2,8 +2,13 @@ package repository
 
 import (
 	"app/internal/entity"
+	"app/internal/tenant"
 	"context"
 	"database/sql"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
 )
 
 type TaskRepo struct {
@@ -14,50 +19,350 @@ func NewTaskRepo(db *sql.DB) *TaskRepo {
 	return &TaskRepo{db: db}
 }
 
+// commentCountExpr и checklistExpr считают комментарии и пункты чек-листа
+// задачи; запросы с taskColumns должны читать из tasks без алиаса.
+const (
+	commentCountExpr = `(SELECT count(*) FROM comments c WHERE c.task_id = tasks.id)`
+	checklistExpr    = `(SELECT count(*) FILTER (WHERE ci.done) || '/' || count(*) FROM checklist_items ci WHERE ci.task_id = tasks.id)`
+)
+
+const taskColumns = `id, owner_id, workspace_id, assignee_id, project_id, parent_id, title, description, status, priority, start_at, due_at, created_at, updated_at, recurrence, recurrence_start, ` +
+	commentCountExpr + `, ` + checklistExpr
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// taskDest — приёмники для колонок taskColumns.
+func taskDest(t *entity.Task) []any {
+	return []any{
+		&t.ID, &t.OwnerID, &t.WorkspaceID, &t.AssigneeID, &t.ProjectID, &t.ParentID, &t.Title, &t.Description, &t.Status, &t.Priority, &t.StartAt, &t.DueAt, &t.CreatedAt, &t.UpdatedAt, &t.Recurrence, &t.RecurrenceStart, &t.CommentCount, checklistDest{&t.Checklist},
+	}
+}
+
+// checklistDest разбирает checklistExpr («done/total»); без пунктов Checklist остаётся nil.
+type checklistDest struct {
+	p **entity.ChecklistProgress
+}
+
+func (d checklistDest) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case nil:
+	default:
+		return fmt.Errorf("checklist progress: unexpected %T", src)
+	}
+	doneStr, totalStr, _ := strings.Cut(s, "/")
+	done, _ := strconv.Atoi(doneStr)
+	total, _ := strconv.Atoi(totalStr)
+	if total == 0 {
+		*d.p = nil
+		return nil
+	}
+	*d.p = &entity.ChecklistProgress{Done: done, Total: total, Summary: s}
+	return nil
+}
+
+func scanTask(row rowScanner) (*entity.Task, error) {
+	var t entity.Task
+	if err := row.Scan(taskDest(&t)...); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// sortColumn описывает колонку из белого списка сортировки. Для nullable-колонок
+// NULL заменяется на ±infinity так, чтобы задачи без даты оказывались в конце
+// при любом направлении, а сравнение по курсору не спотыкалось о NULL.
+type sortColumn struct {
+	name     string
+	cast     string
+	nullable bool
+}
+
+var sortColumns = map[entity.TaskSortField]sortColumn{
+	entity.SortByID:        {name: "id", cast: "bigint"},
+	entity.SortByTitle:     {name: "title", cast: "text"},
+	entity.SortByPriority:  {name: "priority", cast: "smallint"},
+	entity.SortByStartAt:   {name: "start_at", cast: "timestamptz", nullable: true},
+	entity.SortByDueAt:     {name: "due_at", cast: "timestamptz", nullable: true},
+	entity.SortByCreatedAt: {name: "created_at", cast: "timestamptz"},
+	entity.SortByUpdatedAt: {name: "updated_at", cast: "timestamptz"},
+}
+
+// expr возвращает выражение для колонки (или для параметра, если param != "").
+func (c sortColumn) expr(desc bool, param string) string {
+	e := c.name
+	if param != "" {
+		e = param + "::" + c.cast
+	}
+	if !c.nullable {
+		return e
+	}
+	if desc {
+		return "COALESCE(" + e + ", '-infinity')"
+	}
+	return "COALESCE(" + e + ", 'infinity')"
+}
+
+func orderBy(keys []entity.TaskSort) string {
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		col := sortColumns[k.Field]
+		if k.Desc {
+			parts = append(parts, col.expr(true, "")+" DESC")
+		} else {
+			parts = append(parts, col.expr(false, "")+" ASC")
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// keysetCondition — условие «строго после курсора» для порядка keys:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ..., с < для убывающих ключей.
+func keysetCondition(keys []entity.TaskSort, cur *entity.TaskCursor, args *sqlArgs) string {
+	var (
+		ors    []string
+		prefix []string
+	)
+	for i, k := range keys {
+		col := sortColumns[k.Field]
+		param := args.add(cur.Values[i])
+		op := ">"
+		if k.Desc {
+			op = "<"
+		}
+		cond := append(append([]string{}, prefix...), col.expr(k.Desc, "")+" "+op+" "+col.expr(k.Desc, param))
+		ors = append(ors, "("+strings.Join(cond, " AND ")+")")
+		prefix = append(prefix, col.expr(k.Desc, "")+" = "+col.expr(k.Desc, param))
+	}
+	return "(" + strings.Join(ors, " OR ") + ")"
+}
+
+// sqlArgs копит параметры запроса и возвращает их плейсхолдеры.
+type sqlArgs []any
+
+func (a *sqlArgs) add(v any) string {
+	*a = append(*a, v)
+	return "$" + strconv.Itoa(len(*a))
+}
+
+// sharedWith — условие «пользователю из параметра param выдан доступ
+// к задаче или к её проекту».
+func sharedWith(param string) string {
+	return `(EXISTS (SELECT 1 FROM task_shares s WHERE s.task_id = tasks.id AND s.user_id = ` + param + `)
+		OR EXISTS (SELECT 1 FROM project_shares s WHERE s.project_id = tasks.project_id AND s.user_id = ` + param + `))`
+}
+
+// taskConditions — условия WHERE для списка задач, кроме позиции курсора.
+// Первым условием всегда идёт текущее рабочее пространство из контекста.
+func taskConditions(ctx context.Context, userID int64, q entity.TaskQuery, args *sqlArgs) ([]string, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	where := []string{"workspace_id = " + args.add(workspaceID)}
+	switch q.Scope {
+	case entity.ScopeShared:
+		p := args.add(userID)
+		where = append(where, "owner_id <> "+p+" AND "+sharedWith(p))
+	case entity.ScopeAll:
+		p := args.add(userID)
+		where = append(where, "(owner_id = "+p+" OR "+sharedWith(p)+")")
+	default:
+		where = append(where, "owner_id = "+args.add(userID))
+	}
+	if q.ProjectID != nil {
+		where = append(where, "project_id = "+args.add(*q.ProjectID))
+	}
+	if q.ParentID != nil {
+		where = append(where, "parent_id = "+args.add(*q.ParentID))
+	}
+	if q.AssigneeID != nil {
+		where = append(where, "assignee_id = "+args.add(*q.AssigneeID))
+	}
+	if q.DueBefore != nil {
+		where = append(where, "due_at < "+args.add(*q.DueBefore))
+	}
+	if q.Overdue {
+		where = append(where, statusCategoryExpr+" = '"+string(entity.CategoryOpen)+"'")
+	}
+	for _, name := range q.Labels {
+		where = append(where, hasLabel(args.add(name)))
+	}
+	if q.Filter != nil {
+		cond, err := compileFilter(q.Filter, args)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, cond)
+	}
+	return where, nil
+}
+
+// Create заводит задачу в текущем рабочем пространстве.
 func (r *TaskRepo) Create(ctx context.Context, task *entity.Task) (*entity.Task, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
 	const query = `
-		INSERT INTO tasks (owner_id, title, description, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, now(), now())
+		INSERT INTO tasks (owner_id, workspace_id, project_id, parent_id, title, description, status, priority, start_at, due_at, recurrence, recurrence_start, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, now(), now())
 		RETURNING id, created_at, updated_at
 	`
 
 	if err := r.db.QueryRowContext(ctx, query,
 		task.OwnerID,
+		workspaceID,
+		task.ProjectID,
+		task.ParentID,
 		task.Title,
 		task.Description,
 		task.Status,
+		task.Priority,
+		task.StartAt,
+		task.DueAt,
+		task.Recurrence,
+		task.RecurrenceStart,
 	).Scan(&task.ID, &task.CreatedAt, &task.UpdatedAt); err != nil {
 		return nil, err
 	}
+	task.WorkspaceID = workspaceID
+	task.Labels = []entity.Label{}
 	return task, nil
 }
 
 func (r *TaskRepo) Update(ctx context.Context, task *entity.Task) (*entity.Task, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := updateTask(ctx, r.db, task, workspaceID); err != nil {
+		return nil, err
+	}
+	if err := r.loadLabels(ctx, []*entity.Task{task}); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// UpdateStatus сохраняет задачу со сменой статуса вместе с effects в одной
+// транзакции: при ошибке не остаётся ни нового статуса без повторения или
+// подзадач, ни повторения без выполненной задачи. false — подзадача из
+// effects.Cascade успела сменить статус, ничего не сохранено.
+func (r *TaskRepo) UpdateStatus(ctx context.Context, task *entity.Task, effects *entity.StatusEffects) (bool, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if effects.Next != nil {
+		// повторение создаётся до UPDATE задачи: снятое правило не должно
+		// вернуться в неё из task
+		if effects.Next, err = spawnOccurrence(ctx, tx, task, effects.Next, workspaceID); err != nil {
+			return false, err
+		}
+	}
+	if err := updateTask(ctx, tx, task, workspaceID); err != nil {
+		return false, err
+	}
+	for i := range effects.Cascade {
+		c := &effects.Cascade[i]
+		res, err := tx.ExecContext(ctx, `
+			UPDATE tasks
+			SET status = $1, updated_at = now()
+			WHERE id = $2 AND owner_id = $3 AND workspace_id = $4 AND status = $5
+		`, c.Task.Status, c.Task.ID, c.Task.OwnerID, workspaceID, c.From)
+		if err != nil {
+			return false, err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return false, nil
+		}
+		if c.Next != nil {
+			if c.Next, err = spawnOccurrence(ctx, tx, c.Task, c.Next, workspaceID); err != nil {
+				return false, err
+			}
+		}
+	}
+	if effects.DetachChildren {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE tasks
+			SET parent_id = NULL, updated_at = now()
+			WHERE parent_id = $1 AND owner_id = $2 AND workspace_id = $3
+		`, task.ID, task.OwnerID, workspaceID)
+		if err != nil {
+			return false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	tasks := []*entity.Task{task}
+	if effects.Next != nil {
+		tasks = append(tasks, effects.Next)
+	}
+	return true, r.loadLabels(ctx, tasks)
+}
+
+// dbtx — общее у *sql.DB и *sql.Tx, чтобы один запрос можно было выполнить
+// и отдельно, и внутри транзакции.
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func updateTask(ctx context.Context, db dbtx, task *entity.Task, workspaceID int64) error {
 	const query = `
 		UPDATE tasks
 		SET title = $1,
 		    description = $2,
 		    status = $3,
+		    priority = $4,
+		    start_at = $5,
+		    due_at = $6,
+		    project_id = $7,
+		    recurrence = $8,
+		    recurrence_start = $9,
 		    updated_at = now()
-		WHERE id = $4 AND owner_id = $5
-		RETURNING created_at, updated_at
+		WHERE id = $10 AND owner_id = $11 AND workspace_id = $12
+		RETURNING workspace_id, created_at, updated_at, ` + commentCountExpr + `, ` + checklistExpr + `
 	`
 
-	if err := r.db.QueryRowContext(ctx, query,
+	return db.QueryRowContext(ctx, query,
 		task.Title,
 		task.Description,
 		task.Status,
+		task.Priority,
+		task.StartAt,
+		task.DueAt,
+		task.ProjectID,
+		task.Recurrence,
+		task.RecurrenceStart,
 		task.ID,
 		task.OwnerID,
-	).Scan(&task.CreatedAt, &task.UpdatedAt); err != nil {
-		return nil, err
-	}
-	return task, nil
+		workspaceID,
+	).Scan(&task.WorkspaceID, &task.CreatedAt, &task.UpdatedAt, &task.CommentCount, checklistDest{&task.Checklist})
 }
 
 func (r *TaskRepo) Delete(ctx context.Context, id int64, ownerID int64) error {
-	const query = `DELETE FROM tasks WHERE id = $1 AND owner_id = $2`
-	res, err := r.db.ExecContext(ctx, query, id, ownerID)
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	const query = `DELETE FROM tasks WHERE id = $1 AND owner_id = $2 AND workspace_id = $3`
+	res, err := r.db.ExecContext(ctx, query, id, ownerID, workspaceID)
 	if err != nil {
 		return err
 	}
@@ -69,28 +374,45 @@ func (r *TaskRepo) Delete(ctx context.Context, id int64, ownerID int64) error {
 }
 
 func (r *TaskRepo) GetByID(ctx context.Context, id int64, ownerID int64) (*entity.Task, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
 	const query = `
-		SELECT id, owner_id, title, description, status, created_at, updated_at
+		SELECT ` + taskColumns + `
 		FROM tasks
-		WHERE id = $1 AND owner_id = $2
+		WHERE id = $1 AND owner_id = $2 AND workspace_id = $3
 	`
-	var t entity.Task
-	if err := r.db.QueryRowContext(ctx, query, id, ownerID).Scan(
-		&t.ID, &t.OwnerID, &t.Title, &t.Description, &t.Status, &t.CreatedAt, &t.UpdatedAt,
-	); err != nil {
+	t, err := scanTask(r.db.QueryRowContext(ctx, query, id, ownerID, workspaceID))
+	if err != nil {
 		return nil, err
 	}
-	return &t, nil
+	if err := r.loadLabels(ctx, []*entity.Task{t}); err != nil {
+		return nil, err
+	}
+	return t, nil
 }
 
-func (r *TaskRepo) List(ctx context.Context, ownerID int64) ([]*entity.Task, error) {
-	const query = `
-		SELECT id, owner_id, title, description, status, created_at, updated_at
+func (r *TaskRepo) List(ctx context.Context, userID int64, q entity.TaskQuery) ([]*entity.Task, error) {
+	var args sqlArgs
+	keys := q.OrderKeys()
+	where, err := taskConditions(ctx, userID, q, &args)
+	if err != nil {
+		return nil, err
+	}
+	if q.After != nil {
+		where = append(where, keysetCondition(keys, q.After, &args))
+	}
+
+	query := `
+		SELECT ` + taskColumns + `
 		FROM tasks
-		WHERE owner_id = $1
-		ORDER BY id DESC
-	`
-	rows, err := r.db.QueryContext(ctx, query, ownerID)
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY ` + orderBy(keys)
+	if q.Limit > 0 {
+		query += ` LIMIT ` + args.add(q.Limit)
+	}
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -98,14 +420,156 @@ func (r *TaskRepo) List(ctx context.Context, ownerID int64) ([]*entity.Task, err
 
 	var tasks []*entity.Task
 	for rows.Next() {
-		var t entity.Task
-		if err := rows.Scan(&t.ID, &t.OwnerID, &t.Title, &t.Description, &t.Status, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		t, err := scanTask(rows)
+		if err != nil {
 			return nil, err
 		}
-		tasks = append(tasks, &t)
+		tasks = append(tasks, t)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	if err := r.loadLabels(ctx, tasks); err != nil {
+		return nil, err
+	}
 	return tasks, nil
 }
+
+// loadLabels одним запросом подгружает метки для всех переданных задач.
+func (r *TaskRepo) loadLabels(ctx context.Context, tasks []*entity.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(tasks))
+	byID := make(map[int64]*entity.Task, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+		byID[t.ID] = t
+		t.Labels = []entity.Label{}
+	}
+
+	const query = `
+		SELECT tl.task_id, l.id, l.owner_id, l.workspace_id, l.name, l.color, l.created_at, l.updated_at
+		FROM task_labels tl
+		JOIN labels l ON l.id = tl.label_id
+		WHERE tl.task_id = ANY($1)
+		ORDER BY lower(l.name)
+	`
+	rows, err := r.db.QueryContext(ctx, query, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			taskID int64
+			l      entity.Label
+		)
+		if err := rows.Scan(&taskID, &l.ID, &l.OwnerID, &l.WorkspaceID, &l.Name, &l.Color, &l.CreatedAt, &l.UpdatedAt); err != nil {
+			return err
+		}
+		if t, ok := byID[taskID]; ok {
+			t.Labels = append(t.Labels, l)
+		}
+	}
+	return rows.Err()
+}
+
+func (r *TaskRepo) Count(ctx context.Context, userID int64, q entity.TaskQuery) (int64, error) {
+	var args sqlArgs
+	where, err := taskConditions(ctx, userID, q, &args)
+	if err != nil {
+		return 0, err
+	}
+	query := `SELECT count(*) FROM tasks WHERE ` + strings.Join(where, " AND ")
+
+	var n int64
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Маркеры совпадений для ts_headline: управляющие символы не встречаются в
+// обычном тексте, поэтому после HTML-экранирования их можно заменить на <mark>.
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+var highlightReplacer = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+
+func highlight(s string) string {
+	return highlightReplacer.Replace(html.EscapeString(s))
+}
+
+// Search ищет задачи по tsquery (в синтаксисе to_tsquery) и ранжирует по ts_rank_cd.
+func (r *TaskRepo) Search(ctx context.Context, ownerID int64, tsquery string, limit, offset int) ([]*entity.TaskSearchHit, error) {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	const query = `
+		WITH q AS (SELECT to_tsquery('russian', $2) AS query)
+		SELECT ` + taskColumns + `,
+		       ts_rank_cd(search_vector, q.query) AS rank,
+		       ts_headline('russian', title, q.query, $5),
+		       ts_headline('russian', coalesce(description, ''), q.query, $6)
+		FROM tasks, q
+		WHERE owner_id = $1 AND workspace_id = $7 AND search_vector @@ q.query
+		ORDER BY rank DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`
+	sel := "StartSel=" + highlightStart + ", StopSel=" + highlightStop
+	titleOpts := sel + ", HighlightAll=true"
+	descOpts := sel + `, MaxFragments=2, MaxWords=25, MinWords=8, FragmentDelimiter=" … "`
+
+	rows, err := r.db.QueryContext(ctx, query, ownerID, tsquery, limit, offset, titleOpts, descOpts, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []*entity.TaskSearchHit
+	for rows.Next() {
+		hit := &entity.TaskSearchHit{Task: &entity.Task{}}
+		dest := append(taskDest(hit.Task), &hit.Rank, &hit.TitleSnippet, &hit.DescriptionSnippet)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		hit.TitleSnippet = highlight(hit.TitleSnippet)
+		hit.DescriptionSnippet = highlight(hit.DescriptionSnippet)
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*entity.Task, len(hits))
+	for i, hit := range hits {
+		tasks[i] = hit.Task
+	}
+	if err := r.loadLabels(ctx, tasks); err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// SetAssignee назначает исполнителя задачи; nil снимает назначение.
+func (r *TaskRepo) SetAssignee(ctx context.Context, id, ownerID int64, assigneeID *int64) error {
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return err
+	}
+	const query = `UPDATE tasks SET assignee_id = $1, updated_at = now() WHERE id = $2 AND owner_id = $3 AND workspace_id = $4`
+	res, err := r.db.ExecContext(ctx, query, assigneeID, id, ownerID, workspaceID)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"app/internal/entity"
 	"context"
 	"database/sql"
+	"strings"
+	"time"
 )
 
 type UserRepo struct {
@@ -14,47 +16,377 @@ func NewUserRepo(db *sql.DB) *UserRepo {
 	return &UserRepo{db: db}
 }
 
+// Register создаёт пользователя вместе с его личным рабочим пространством
+// и системной ролью по умолчанию.
 func (r *UserRepo) Register(ctx context.Context, user *entity.User) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
 	const q = `
 		INSERT INTO users (email, password_hash, description, created_at, updated_at)
 		VALUES ($1, $2, $3, now(), now())
 		RETURNING id
 	`
 	var id int64
-	if err := r.db.QueryRowContext(ctx, q, user.Email, user.PasswordHash, user.Description).Scan(&id); err != nil {
+	if err := tx.QueryRowContext(ctx, q, user.Email, user.PasswordHash, user.Description).Scan(&id); err != nil {
+		return 0, err
+	}
+	if err := createPersonal(ctx, tx, id); err != nil {
+		return 0, err
+	}
+	if err := grantDefaultRole(ctx, tx, id); err != nil {
 		return 0, err
 	}
-	return id, nil
+	return id, tx.Commit()
 }
 
-func (r *UserRepo) GetByID(ctx context.Context, id int64) (*entity.User, error) {
-	const q = `
-		SELECT id, email, password_hash, description, created_at, updated_at
-		FROM users
-		WHERE id = $1
-		LIMIT 1
-	`
+const userColumns = `id, email, password_hash, description, created_at, updated_at, disabled_at, must_reset_password, tokens_valid_after, email_verified_at`
+
+func scanUser(row rowScanner) (*entity.User, error) {
 	var u entity.User
-	if err := r.db.QueryRowContext(ctx, q, id).Scan(
+	if err := row.Scan(
 		&u.ID, &u.Email, &u.PasswordHash, &u.Description, &u.CreatedAt, &u.UpdatedAt,
+		&u.DisabledAt, &u.MustResetPassword, &u.TokensValidAfter, &u.EmailVerifiedAt,
 	); err != nil {
 		return nil, err
 	}
 	return &u, nil
 }
 
-func (r *UserRepo) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+func (r *UserRepo) GetByID(ctx context.Context, id int64) (*entity.User, error) {
 	const q = `
-		SELECT id, email, password_hash, description, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
-		WHERE email = $1
+		WHERE id = $1
 		LIMIT 1
 	`
-	var u entity.User
-	if err := r.db.QueryRowContext(ctx, q, email).Scan(
-		&u.ID, &u.Email, &u.PasswordHash, &u.Description, &u.CreatedAt, &u.UpdatedAt,
-	); err != nil {
+	return scanUser(r.db.QueryRowContext(ctx, q, id))
+}
+
+// GetByEmail ищет пользователя по почте без учёта регистра (через
+// уникальный индекс users_email_lower_key).
+func (r *UserRepo) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	const q = `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE lower(email) = lower($1)
+	`
+	return scanUser(r.db.QueryRowContext(ctx, q, email))
+}
+
+// List возвращает страницу пользователей, чья почта содержит search (пусто —
+// все), и общее число найденных.
+func (r *UserRepo) List(ctx context.Context, search string, limit, offset int) ([]*entity.User, int64, error) {
+	const q = `
+		SELECT ` + userColumns + `, count(*) OVER ()
+		FROM users
+		WHERE $1 = '' OR lower(email) LIKE '%' || lower($1) || '%'
+		ORDER BY id
+		LIMIT $2 OFFSET $3
+	`
+	search = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(search)
+	rows, err := r.db.QueryContext(ctx, q, search, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var (
+		users = []*entity.User{}
+		total int64
+	)
+	for rows.Next() {
+		var u entity.User
+		if err := rows.Scan(
+			&u.ID, &u.Email, &u.PasswordHash, &u.Description, &u.CreatedAt, &u.UpdatedAt,
+			&u.DisabledAt, &u.MustResetPassword, &u.TokensValidAfter, &u.EmailVerifiedAt, &total,
+		); err != nil {
+			return nil, 0, err
+		}
+		users = append(users, &u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// SetDisabled отключает аккаунт или включает его обратно.
+func (r *UserRepo) SetDisabled(ctx context.Context, id int64, disabled bool) error {
+	const q = `
+		UPDATE users
+		SET disabled_at = CASE WHEN $2 THEN COALESCE(disabled_at, now()) END,
+		    updated_at = now()
+		WHERE id = $1
+	`
+	res, err := r.db.ExecContext(ctx, q, id, disabled)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Delete удаляет пользователя; его задачи, проекты и личное пространство
+// удаляются каскадно.
+func (r *UserRepo) Delete(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// TaskCounts считает задачи, которыми владеет пользователь, по статусам;
+// просроченными считаются задачи со сроком в прошлом и статусом не из closed.
+func (r *UserRepo) TaskCounts(ctx context.Context, id int64, closed []entity.TaskStatus) (*entity.UserTaskCounts, error) {
+	counts := &entity.UserTaskCounts{UserID: id, ByStatus: map[entity.TaskStatus]int64{}}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT status, count(*), count(*) FILTER (WHERE due_at < now() AND status <> ALL($2))
+		FROM tasks
+		WHERE owner_id = $1
+		GROUP BY status
+	`, id, statusStrings(closed))
+	if err != nil {
 		return nil, err
 	}
-	return &u, nil
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			status         entity.TaskStatus
+			total, overdue int64
+		)
+		if err := rows.Scan(&status, &total, &overdue); err != nil {
+			return nil, err
+		}
+		counts.ByStatus[status] = total
+		counts.Total += total
+		counts.Overdue += overdue
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRowContext(ctx, `SELECT count(*) FROM tasks WHERE assignee_id = $1`, id).Scan(&counts.Assigned)
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// RequirePasswordReset запрещает вход по текущему паролю и сохраняет токен
+// сброса. Прежние невыполненные сбросы пользователя перестают действовать.
+func (r *UserRepo) RequirePasswordReset(ctx context.Context, id int64, tokenHash string, expiresAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE users SET must_reset_password = true, updated_at = now() WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	if err := createPasswordReset(ctx, tx, id, tokenHash, expiresAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CreatePasswordReset сохраняет токен сброса, который пользователь запросил
+// сам. Если с since он уже запрашивал сброс, ничего не делает и возвращает
+// false — чтобы письма нельзя было слать без конца.
+func (r *UserRepo) CreatePasswordReset(ctx context.Context, userID int64, tokenHash string, expiresAt, since time.Time) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// блокировка строки пользователя упорядочивает параллельные запросы
+	var recent bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM password_resets
+			WHERE user_id = u.id AND used_at IS NULL AND created_at >= $2
+		)
+		FROM users u
+		WHERE u.id = $1
+		FOR UPDATE OF u
+	`, userID, since).Scan(&recent)
+	if err != nil {
+		return false, err
+	}
+	if recent {
+		return false, nil
+	}
+	if err := createPasswordReset(ctx, tx, userID, tokenHash, expiresAt); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func createPasswordReset(ctx context.Context, tx *sql.Tx, userID int64, tokenHash string, expiresAt time.Time) error {
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM password_resets WHERE user_id = $1 AND used_at IS NULL`, userID); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO password_resets (user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, now())
+	`, userID, tokenHash, expiresAt)
+	return err
+}
+
+// ResetPassword задаёт новый пароль по действующему токену сброса и
+// возвращает id пользователя. Токен одноразовый: строка блокируется и
+// помечается использованной в той же транзакции. Все токены пользователя,
+// выданные до tokensValidAfter, отзываются. Для неизвестного, истёкшего
+// или использованного токена — sql.ErrNoRows.
+func (r *UserRepo) ResetPassword(ctx context.Context, tokenHash string, passwordHash []byte, tokensValidAfter time.Time) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var resetID, userID int64
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, user_id
+		FROM password_resets
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > now()
+		FOR UPDATE
+	`, tokenHash).Scan(&resetID, &userID)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE password_resets SET used_at = now() WHERE id = $1`, resetID); err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE users
+		SET password_hash = $2, must_reset_password = false, updated_at = now()
+		WHERE id = $1
+	`, userID, passwordHash); err != nil {
+		return 0, err
+	}
+	if err := revokeTokens(ctx, tx, userID, tokensValidAfter); err != nil {
+		return 0, err
+	}
+	return userID, tx.Commit()
+}
+
+// RevokeTokens отзывает все токены пользователя, выданные до validAfter,
+// и все его сессии вместе с refresh-токенами.
+func (r *UserRepo) RevokeTokens(ctx context.Context, id int64, validAfter time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := revokeTokens(ctx, tx, id, validAfter); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func revokeTokens(ctx context.Context, tx *sql.Tx, userID int64, validAfter time.Time) error {
+	res, err := tx.ExecContext(ctx,
+		`UPDATE users SET tokens_valid_after = $2 WHERE id = $1`, userID, validAfter)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return revokeSessions(ctx, tx, userID)
+}
+
+// CreateEmailVerification сохраняет токен подтверждения почты. Прежние
+// неиспользованные токены пользователя перестают действовать. Если с since
+// токен уже выписывался, ничего не делает и возвращает false.
+func (r *UserRepo) CreateEmailVerification(ctx context.Context, userID int64, tokenHash string, expiresAt, since time.Time) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var recent bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM email_verifications
+			WHERE user_id = u.id AND used_at IS NULL AND created_at >= $2
+		)
+		FROM users u
+		WHERE u.id = $1
+		FOR UPDATE OF u
+	`, userID, since).Scan(&recent)
+	if err != nil {
+		return false, err
+	}
+	if recent {
+		return false, nil
+	}
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM email_verifications WHERE user_id = $1 AND used_at IS NULL`, userID); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO email_verifications (user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, now())
+	`, userID, tokenHash, expiresAt); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// VerifyEmail подтверждает почту по действующему токену и возвращает id
+// пользователя. Токен одноразовый. Для неизвестного, истёкшего или
+// использованного токена — sql.ErrNoRows.
+func (r *UserRepo) VerifyEmail(ctx context.Context, tokenHash string) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var verificationID, userID int64
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, user_id
+		FROM email_verifications
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > now()
+		FOR UPDATE
+	`, tokenHash).Scan(&verificationID, &userID)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE email_verifications SET used_at = now() WHERE id = $1`, verificationID); err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE users
+		SET email_verified_at = COALESCE(email_verified_at, now()), updated_at = now()
+		WHERE id = $1
+	`, userID); err != nil {
+		return 0, err
+	}
+	return userID, tx.Commit()
 }
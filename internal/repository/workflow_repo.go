@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"app/internal/entity"
+	"context"
+	"database/sql"
+)
+
+type WorkflowRepo struct {
+	db *sql.DB
+}
+
+func NewWorkflowRepo(db *sql.DB) *WorkflowRepo {
+	return &WorkflowRepo{db: db}
+}
+
+func (r *WorkflowRepo) ListStates(ctx context.Context, ownerID int64) ([]entity.WorkflowState, error) {
+	const query = `
+		SELECT key, name, category
+		FROM workflow_states
+		WHERE owner_id = $1
+		ORDER BY created_at, key
+	`
+	rows, err := r.db.QueryContext(ctx, query, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var states []entity.WorkflowState
+	for rows.Next() {
+		s := entity.WorkflowState{Custom: true}
+		if err := rows.Scan(&s.Key, &s.Name, &s.Category); err != nil {
+			return nil, err
+		}
+		states = append(states, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+func (r *WorkflowRepo) ListTransitions(ctx context.Context, ownerID int64) ([]entity.WorkflowTransition, error) {
+	const query = `
+		SELECT from_status, to_status
+		FROM workflow_transitions
+		WHERE owner_id = $1
+		ORDER BY from_status, to_status
+	`
+	rows, err := r.db.QueryContext(ctx, query, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transitions []entity.WorkflowTransition
+	for rows.Next() {
+		t := entity.WorkflowTransition{Custom: true}
+		if err := rows.Scan(&t.From, &t.To); err != nil {
+			return nil, err
+		}
+		transitions = append(transitions, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+func (r *WorkflowRepo) CreateState(ctx context.Context, ownerID int64, state entity.WorkflowState) error {
+	const query = `
+		INSERT INTO workflow_states (owner_id, key, name, category, created_at)
+		VALUES ($1, $2, $3, $4, now())
+	`
+	_, err := r.db.ExecContext(ctx, query, ownerID, state.Key, state.Name, state.Category)
+	return err
+}
+
+// DeleteState удаляет пользовательское состояние вместе со всеми переходами,
+// в которых оно участвует.
+func (r *WorkflowRepo) DeleteState(ctx context.Context, ownerID int64, key entity.TaskStatus) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM workflow_states WHERE owner_id = $1 AND key = $2`, ownerID, key)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	const query = `
+		DELETE FROM workflow_transitions
+		WHERE owner_id = $1 AND (from_status = $2 OR to_status = $2)
+	`
+	if _, err := tx.ExecContext(ctx, query, ownerID, key); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// StateInUse сообщает, есть ли у пользователя задачи в этом состоянии.
+func (r *WorkflowRepo) StateInUse(ctx context.Context, ownerID int64, key entity.TaskStatus) (bool, error) {
+	const query = `SELECT EXISTS (SELECT 1 FROM tasks WHERE owner_id = $1 AND status = $2)`
+	var inUse bool
+	if err := r.db.QueryRowContext(ctx, query, ownerID, key).Scan(&inUse); err != nil {
+		return false, err
+	}
+	return inUse, nil
+}
+
+func (r *WorkflowRepo) AddTransition(ctx context.Context, ownerID int64, from, to entity.TaskStatus) error {
+	const query = `
+		INSERT INTO workflow_transitions (owner_id, from_status, to_status)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, ownerID, from, to)
+	return err
+}
+
+func (r *WorkflowRepo) DeleteTransition(ctx context.Context, ownerID int64, from, to entity.TaskStatus) error {
+	const query = `
+		DELETE FROM workflow_transitions
+		WHERE owner_id = $1 AND from_status = $2 AND to_status = $3
+	`
+	res, err := r.db.ExecContext(ctx, query, ownerID, from, to)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
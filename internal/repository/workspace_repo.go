@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"app/internal/entity"
+	"context"
+	"database/sql"
+)
+
+type WorkspaceRepo struct {
+	db *sql.DB
+}
+
+func NewWorkspaceRepo(db *sql.DB) *WorkspaceRepo {
+	return &WorkspaceRepo{db: db}
+}
+
+// Create заводит пространство и делает adminID его администратором.
+func (r *WorkspaceRepo) Create(ctx context.Context, name string, adminID int64) (*entity.Workspace, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	w := &entity.Workspace{Name: name, Role: entity.WorkspaceAdmin}
+	if err := tx.QueryRowContext(ctx,
+		`INSERT INTO workspaces (name, created_at) VALUES ($1, now()) RETURNING id, created_at`,
+		name).Scan(&w.ID, &w.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := addMember(ctx, tx, w.ID, adminID, entity.WorkspaceAdmin); err != nil {
+		return nil, err
+	}
+	return w, tx.Commit()
+}
+
+// createPersonal заводит личное пространство пользователя внутри транзакции регистрации.
+func createPersonal(ctx context.Context, tx *sql.Tx, userID int64) error {
+	var id int64
+	if err := tx.QueryRowContext(ctx,
+		`INSERT INTO workspaces (name, personal_owner_id, created_at) VALUES ('Личное', $1, now()) RETURNING id`,
+		userID).Scan(&id); err != nil {
+		return err
+	}
+	return addMember(ctx, tx, id, userID, entity.WorkspaceAdmin)
+}
+
+func addMember(ctx context.Context, tx *sql.Tx, workspaceID, userID int64, role entity.WorkspaceRole) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO workspace_members (workspace_id, user_id, role, created_at)
+		VALUES ($1, $2, $3, now())
+		ON CONFLICT (workspace_id, user_id) DO NOTHING
+	`, workspaceID, userID, role)
+	return err
+}
+
+// ListForUser возвращает пространства, где userID — участник, с его ролью.
+func (r *WorkspaceRepo) ListForUser(ctx context.Context, userID int64) ([]*entity.Workspace, error) {
+	const query = `
+		SELECT w.id, w.name, w.personal_owner_id IS NOT NULL, w.created_at, m.role
+		FROM workspaces w
+		JOIN workspace_members m ON m.workspace_id = w.id
+		WHERE m.user_id = $1
+		ORDER BY w.personal_owner_id IS NULL, w.id
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	workspaces := []*entity.Workspace{}
+	for rows.Next() {
+		var w entity.Workspace
+		if err := rows.Scan(&w.ID, &w.Name, &w.Personal, &w.CreatedAt, &w.Role); err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, &w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return workspaces, nil
+}
+
+const membershipColumns = `m.workspace_id, m.user_id, u.email, m.role, m.created_at`
+
+func scanMembership(row rowScanner) (*entity.Membership, error) {
+	var m entity.Membership
+	if err := row.Scan(&m.WorkspaceID, &m.UserID, &m.Email, &m.Role, &m.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Membership возвращает членство userID в пространстве или sql.ErrNoRows.
+func (r *WorkspaceRepo) Membership(ctx context.Context, workspaceID, userID int64) (*entity.Membership, error) {
+	const query = `
+		SELECT ` + membershipColumns + `
+		FROM workspace_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.workspace_id = $1 AND m.user_id = $2
+	`
+	return scanMembership(r.db.QueryRowContext(ctx, query, workspaceID, userID))
+}
+
+// Personal возвращает членство пользователя в его личном пространстве.
+func (r *WorkspaceRepo) Personal(ctx context.Context, userID int64) (*entity.Membership, error) {
+	const query = `
+		SELECT ` + membershipColumns + `
+		FROM workspace_members m
+		JOIN workspaces w ON w.id = m.workspace_id
+		JOIN users u ON u.id = m.user_id
+		WHERE w.personal_owner_id = $1 AND m.user_id = $1
+	`
+	return scanMembership(r.db.QueryRowContext(ctx, query, userID))
+}
+
+func (r *WorkspaceRepo) Members(ctx context.Context, workspaceID int64) ([]*entity.Membership, error) {
+	const query = `
+		SELECT ` + membershipColumns + `
+		FROM workspace_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.workspace_id = $1
+		ORDER BY m.created_at, m.user_id
+	`
+	rows, err := r.db.QueryContext(ctx, query, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []*entity.Membership{}
+	for rows.Next() {
+		m, err := scanMembership(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// SetRole меняет роль участника. Возвращает false, если так в пространстве
+// не осталось бы ни одного администратора. Проверка и запись идут под
+// блокировкой строки пространства, чтобы два администратора не разжаловали
+// друг друга одновременно.
+func (r *WorkspaceRepo) SetRole(ctx context.Context, workspaceID, userID int64, role entity.WorkspaceRole) (bool, error) {
+	return r.changeMember(ctx, workspaceID, userID, role != entity.WorkspaceAdmin, func(tx *sql.Tx) (sql.Result, error) {
+		return tx.ExecContext(ctx,
+			`UPDATE workspace_members SET role = $3 WHERE workspace_id = $1 AND user_id = $2`,
+			workspaceID, userID, role)
+	})
+}
+
+// RemoveMember исключает участника и отзывает его доступы к задачам и
+// проектам пространства. Последнего администратора исключить нельзя (false).
+func (r *WorkspaceRepo) RemoveMember(ctx context.Context, workspaceID, userID int64) (bool, error) {
+	return r.changeMember(ctx, workspaceID, userID, true, func(tx *sql.Tx) (sql.Result, error) {
+		res, err := tx.ExecContext(ctx,
+			`DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`,
+			workspaceID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			DELETE FROM task_shares s USING tasks t
+			WHERE t.id = s.task_id AND t.workspace_id = $1 AND s.user_id = $2
+		`, workspaceID, userID); err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			DELETE FROM project_shares s USING projects p
+			WHERE p.id = s.project_id AND p.workspace_id = $1 AND s.user_id = $2
+		`, workspaceID, userID); err != nil {
+			return nil, err
+		}
+		return res, nil
+	})
+}
+
+// changeMember выполняет change под блокировкой пространства. Если demotes —
+// участник перестаёт быть администратором, и сначала проверяется, что
+// администратор у пространства останется.
+func (r *WorkspaceRepo) changeMember(ctx context.Context, workspaceID, userID int64, demotes bool, change func(tx *sql.Tx) (sql.Result, error)) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// блокируется строка пространства: так параллельные изменения состава
+	// одного пространства идут по очереди; нет пространства — sql.ErrNoRows
+	var id int64
+	if err := tx.QueryRowContext(ctx, `SELECT id FROM workspaces WHERE id = $1 FOR UPDATE`, workspaceID).Scan(&id); err != nil {
+		return false, err
+	}
+
+	if demotes {
+		var lastAdmin bool
+		err := tx.QueryRowContext(ctx, `
+			SELECT count(*) FILTER (WHERE user_id = $2) = 1 AND count(*) FILTER (WHERE user_id <> $2) = 0
+			FROM workspace_members
+			WHERE workspace_id = $1 AND role = 'admin'
+		`, workspaceID, userID).Scan(&lastAdmin)
+		if err != nil {
+			return false, err
+		}
+		if lastAdmin {
+			return false, nil
+		}
+	}
+
+	res, err := change(tx)
+	if err != nil {
+		return false, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return false, sql.ErrNoRows
+	}
+	return true, tx.Commit()
+}
+
+// CreateInvitation сохраняет приглашение с хэшем токена.
+func (r *WorkspaceRepo) CreateInvitation(ctx context.Context, inv *entity.Invitation, tokenHash string, invitedBy int64) (*entity.Invitation, error) {
+	const query = `
+		INSERT INTO workspace_invitations (workspace_id, email, role, token_hash, invited_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, now())
+		RETURNING id, created_at
+	`
+	if err := r.db.QueryRowContext(ctx, query, inv.WorkspaceID, inv.Email, inv.Role, tokenHash, invitedBy, inv.ExpiresAt).
+		Scan(&inv.ID, &inv.CreatedAt); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// AcceptInvitation принимает действующее приглашение для почты email и
+// добавляет userID в пространство. Приглашение одноразовое: строка
+// блокируется и помечается принятой в той же транзакции. Если приглашения
+// нет, оно истекло, уже принято или выписано на другую почту —
+// sql.ErrNoRows. Если пользователь уже участник, его роль не меняется.
+func (r *WorkspaceRepo) AcceptInvitation(ctx context.Context, tokenHash string, userID int64, email string) (*entity.Membership, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var (
+		id          int64
+		workspaceID int64
+		role        entity.WorkspaceRole
+	)
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, workspace_id, role
+		FROM workspace_invitations
+		WHERE token_hash = $1 AND lower(email) = lower($2)
+		  AND accepted_at IS NULL AND expires_at > now()
+		FOR UPDATE
+	`, tokenHash, email).Scan(&id, &workspaceID, &role)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE workspace_invitations SET accepted_at = now() WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+	if err := addMember(ctx, tx, workspaceID, userID, role); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.Membership(ctx, workspaceID, userID)
+}
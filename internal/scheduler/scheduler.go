@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job — периодическая фоновая задача. Run вызывается сразу после старта и
+// затем каждые Interval; следующий запуск не начнётся, пока не закончится
+// предыдущий.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Scheduler struct {
+	jobs []Job
+}
+
+func New(jobs ...Job) *Scheduler {
+	return &Scheduler{jobs: jobs}
+}
+
+// Run запускает все задачи и блокируется до отмены ctx и завершения
+// текущих запусков.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			loop(ctx, job)
+		}()
+	}
+	wg.Wait()
+}
+
+func loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+	for {
+		runOnce(ctx, job)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce выполняет задачу, не давая ошибке или панике остановить планировщик.
+func runOnce(ctx context.Context, job Job) {
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("scheduler: job %s panicked: %v", job.Name, p)
+		}
+	}()
+	if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("scheduler: job %s: %v", job.Name, err)
+	}
+}
@@ -2,6 +2,8 @@ package security
 
 import (
 	"app/internal/config"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"github.com/golang-jwt/jwt/v5"
 	"time"
@@ -9,18 +11,40 @@ import (
 
 var jwtSecret = []byte(config.C.Secret)
 
+// TimePrecision — точность времени в токене. Секунд мало: отзыв всех
+// токенов пользователя (Claims.IssuedAt раньше отсечки) задел бы токены,
+// выданные в ту же секунду уже после него.
+const TimePrecision = time.Millisecond
+
+func init() {
+	jwt.TimePrecision = TimePrecision
+}
+
 type Claims struct {
 	UserID int64  `json:"user_id"`
 	Email  string `json:"email"`
+	// Roles — системные роли на момент выдачи токена, для клиента; права
+	// сервер проверяет по ролям из базы (см. usecase.RBACUseCase).
+	Roles []string `json:"roles,omitempty"`
+	// SessionID — сессия (вход), в которой выдан токен.
+	SessionID int64 `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateJWT(userID int64, email string) (string, error) {
+// GenerateJWT выписывает токен со случайным jti, по которому его можно отозвать.
+func GenerateJWT(userID int64, email string, roles []string, sessionID int64, expiresAt time.Time) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:    userID,
+		Email:     email,
+		Roles:     roles,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ID:        hex.EncodeToString(b),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
@@ -0,0 +1,25 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// NewToken возвращает случайный одноразовый токен и его хэш. Пользователь
+// получает токен, в базе хранится только хэш.
+func NewToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = hex.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// HashToken — хэш токена для поиска в базе. У токена 256 бит энтропии,
+// поэтому соль и медленный хэш не нужны.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
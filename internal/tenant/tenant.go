@@ -0,0 +1,36 @@
+// Package tenant переносит текущее рабочее пространство через context.Context:
+// AuthMiddleware кладёт его в контекст запроса, репозитории по нему
+// ограничивают каждый запрос к задачам и проектам.
+package tenant
+
+import (
+	"app/internal/entity"
+	"context"
+	"errors"
+)
+
+// ErrNoWorkspace — в контексте нет пространства. Репозитории в этом случае
+// отказываются выполнять запрос, а не читают данные всех пространств.
+var ErrNoWorkspace = errors.New("workspace is not set in context")
+
+type ctxKey struct{}
+
+// With возвращает контекст с членством текущего пользователя в пространстве.
+func With(ctx context.Context, m entity.Membership) context.Context {
+	return context.WithValue(ctx, ctxKey{}, m)
+}
+
+// From возвращает членство, сохранённое With.
+func From(ctx context.Context) (entity.Membership, bool) {
+	m, ok := ctx.Value(ctxKey{}).(entity.Membership)
+	return m, ok
+}
+
+// WorkspaceID возвращает id текущего пространства или ErrNoWorkspace.
+func WorkspaceID(ctx context.Context) (int64, error) {
+	m, ok := From(ctx)
+	if !ok || m.WorkspaceID == 0 {
+		return 0, ErrNoWorkspace
+	}
+	return m.WorkspaceID, nil
+}
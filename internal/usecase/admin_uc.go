@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"app/internal/security"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultUserPageSize = 50
+	maxUserPageSize     = 200
+
+	// passwordResetTTL — сколько действует токен сброса, выписанный администратором.
+	passwordResetTTL = 24 * time.Hour
+)
+
+// AdminUseCase — управление пользователями для администраторов.
+type AdminUseCase struct {
+	users    RepoUser
+	workflow RepoWorkflow
+}
+
+func NewAdminUseCase(users RepoUser, workflow RepoWorkflow) *AdminUseCase {
+	return &AdminUseCase{users: users, workflow: workflow}
+}
+
+// ListUsers ищет пользователей по подстроке почты, постранично.
+func (a *AdminUseCase) ListUsers(ctx context.Context, search string, limit, offset int) ([]*entity.User, int64, error) {
+	switch {
+	case limit < 0 || offset < 0:
+		return nil, 0, fmt.Errorf("%w: limit и offset не могут быть отрицательными", ErrInvalidInput)
+	case limit == 0:
+		limit = defaultUserPageSize
+	case limit > maxUserPageSize:
+		limit = maxUserPageSize
+	}
+	return a.users.List(ctx, strings.TrimSpace(search), limit, offset)
+}
+
+func (a *AdminUseCase) GetUser(ctx context.Context, id int64) (*entity.User, error) {
+	user, err := a.users.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
+
+// SetDisabled отключает или включает аккаунт. Токены отключённого аккаунта
+// перестают приниматься сразу. Себя отключить нельзя.
+func (a *AdminUseCase) SetDisabled(ctx context.Context, adminID, id int64, disabled bool) (*entity.User, error) {
+	if disabled && id == adminID {
+		return nil, fmt.Errorf("%w: нельзя отключить собственный аккаунт", ErrInvalidInput)
+	}
+	if err := a.users.SetDisabled(ctx, id, disabled); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return a.GetUser(ctx, id)
+}
+
+// ForcePasswordReset запрещает вход по текущему паролю и выписывает токен
+// сброса; администратор передаёт его пользователю.
+func (a *AdminUseCase) ForcePasswordReset(ctx context.Context, id int64) (*entity.PasswordReset, error) {
+	token, hash, err := security.NewToken()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(passwordResetTTL)
+	if err := a.users.RequirePasswordReset(ctx, id, hash, expiresAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return &entity.PasswordReset{UserID: id, Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// TaskCounts считает задачи пользователя; просроченные определяются по
+// его собственному workflow.
+func (a *AdminUseCase) TaskCounts(ctx context.Context, id int64) (*entity.UserTaskCounts, error) {
+	if _, err := a.GetUser(ctx, id); err != nil {
+		return nil, err
+	}
+	wf, err := loadWorkflow(ctx, a.workflow, id)
+	if err != nil {
+		return nil, err
+	}
+	var closed []entity.TaskStatus
+	for _, s := range wf.States {
+		if s.Category != entity.CategoryOpen {
+			closed = append(closed, s.Key)
+		}
+	}
+	return a.users.TaskCounts(ctx, id, closed)
+}
+
+// DeleteUser удаляет пользователя вместе с его данными. Себя удалить нельзя.
+func (a *AdminUseCase) DeleteUser(ctx context.Context, adminID, id int64) error {
+	if id == adminID {
+		return fmt.Errorf("%w: нельзя удалить собственный аккаунт", ErrInvalidInput)
+	}
+	if err := a.users.Delete(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	return nil
+}
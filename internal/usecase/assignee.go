@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// SetAssignee назначает исполнителя задачи (nil — снять назначение).
+// Исполнителем может быть только пользователь с доступом к задаче.
+func (t *TaskUseCase) SetAssignee(ctx context.Context, taskID, userID int64, assigneeID *int64) (*entity.Task, error) {
+	ownerID, err := t.access.TaskOwner(ctx, taskID, userID, entity.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if assigneeID != nil {
+		if _, err := t.access.TaskOwner(ctx, taskID, *assigneeID, entity.RoleViewer); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: у пользователя %d нет доступа к задаче", ErrInvalidInput, *assigneeID)
+			}
+			return nil, err
+		}
+	}
+	if err := t.repo.SetAssignee(ctx, taskID, ownerID, assigneeID); err != nil {
+		return nil, err
+	}
+	return t.GetTaskByID(ctx, taskID, userID)
+}
+
+// statusTask загружает задачу для смены статуса. Менять статус может editor,
+// а также исполнитель задачи — даже с ролью viewer; удалять задачу
+// исполнителю это не позволяет.
+func (t *TaskUseCase) statusTask(ctx context.Context, taskID, userID int64) (*entity.Task, error) {
+	task, role, err := t.access.TaskRole(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if role.Allows(entity.RoleEditor) || (task.AssigneeID != nil && *task.AssigneeID == userID) {
+		return task, nil
+	}
+	return nil, fmt.Errorf("%w: менять статус может editor или исполнитель задачи", ErrForbidden)
+}
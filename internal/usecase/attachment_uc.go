@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const blobDeletionBatch = 100
+
+type AttachmentUseCase struct {
+	repo   RepoAttachment
+	access *Authorizer
+	blobs  BlobStore
+	limits entity.AttachmentLimits
+}
+
+func NewAttachmentUseCase(repo RepoAttachment, access *Authorizer, blobs BlobStore, limits entity.AttachmentLimits) *AttachmentUseCase {
+	return &AttachmentUseCase{repo: repo, access: access, blobs: blobs, limits: limits}
+}
+
+func (u *AttachmentUseCase) MaxSize() int64 {
+	return u.limits.MaxSize
+}
+
+// Upload сохраняет файл в хранилище и добавляет вложение к задаче. Тип файла
+// определяется по первым байтам содержимого.
+func (u *AttachmentUseCase) Upload(ctx context.Context, taskID, userID int64, filename string, size int64, file io.ReadSeeker) (*entity.Attachment, error) {
+	if size > u.limits.MaxSize {
+		return nil, fmt.Errorf("%w: максимум %d байт", ErrFileTooLarge, u.limits.MaxSize)
+	}
+	if size == 0 {
+		return nil, fmt.Errorf("%w: пустой файл", ErrInvalidInput)
+	}
+	if _, err := u.access.TaskOwner(ctx, taskID, userID, entity.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	contentType := http.DetectContentType(head[:n])
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if !slices.Contains(u.limits.AllowedTypes, mediaType) {
+		return nil, fmt.Errorf("%w: %s", ErrFileType, mediaType)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	key, err := newStorageKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.blobs.Put(ctx, key, file, size, contentType); err != nil {
+		return nil, err
+	}
+	attachment, err := u.repo.Create(ctx, &entity.Attachment{
+		TaskID:      taskID,
+		UploaderID:  userID,
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  key,
+	})
+	if err != nil {
+		if delErr := u.blobs.Delete(ctx, key); delErr != nil {
+			log.Printf("attachments: failed to remove blob %s after failed insert: %v", key, delErr)
+		}
+		return nil, err
+	}
+	return attachment, nil
+}
+
+func (u *AttachmentUseCase) ListAttachments(ctx context.Context, taskID, userID int64) ([]*entity.Attachment, error) {
+	if _, err := u.access.TaskOwner(ctx, taskID, userID, entity.RoleViewer); err != nil {
+		return nil, err
+	}
+	return u.repo.ListByTask(ctx, taskID)
+}
+
+// Open возвращает вложение и поток с его содержимым; поток нужно закрыть.
+func (u *AttachmentUseCase) Open(ctx context.Context, id, taskID, userID int64) (*entity.Attachment, io.ReadSeekCloser, error) {
+	if _, err := u.access.TaskOwner(ctx, taskID, userID, entity.RoleViewer); err != nil {
+		return nil, nil, err
+	}
+	attachment, err := u.repo.GetByID(ctx, id, taskID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, ErrAttachmentNotFound
+		}
+		return nil, nil, err
+	}
+	content, err := u.blobs.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, ErrAttachmentNotFound
+		}
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// DeleteAttachment удаляет вложение; сам файл удалит CollectGarbage.
+func (u *AttachmentUseCase) DeleteAttachment(ctx context.Context, id, taskID, userID int64) error {
+	if _, err := u.access.TaskOwner(ctx, taskID, userID, entity.RoleEditor); err != nil {
+		return err
+	}
+	if err := u.repo.Delete(ctx, id, taskID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAttachmentNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// CollectGarbage удаляет из хранилища файлы вложений, чьи записи уже удалены —
+// вместе с задачей или по отдельности. Вызывается планировщиком. Файл, который
+// не удалось удалить, остаётся в очереди до следующего запуска и не мешает
+// остальным.
+func (u *AttachmentUseCase) CollectGarbage(ctx context.Context) error {
+	var after string
+	failed := 0
+	for {
+		keys, err := u.repo.PendingBlobDeletions(ctx, after, blobDeletionBatch)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			after = key
+			if err := u.blobs.Delete(ctx, key); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				log.Printf("attachments: failed to delete blob %s: %v", key, err)
+				failed++
+				continue
+			}
+			if err := u.repo.ForgetBlobDeletion(ctx, key); err != nil {
+				return err
+			}
+		}
+		if len(keys) < blobDeletionBatch {
+			break
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d blobs left for the next run", failed)
+	}
+	return nil
+}
+
+// newStorageKey — случайный ключ вида "ab/ab12…"; префикс раскладывает
+// файлы локального хранилища по подкаталогам.
+func newStorageKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	name := hex.EncodeToString(b)
+	return name[:2] + "/" + name, nil
+}
+
+// cleanFilename оставляет от имени файла, присланного клиентом, только
+// базовое имя без управляющих символов.
+func cleanFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	if r := []rune(name); len(r) > 255 {
+		name = string(r[len(r)-255:])
+	}
+	return name
+}
@@ -6,17 +6,66 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
 )
 
 type UserUseCase struct {
-	repo RepoUser
+	repo     RepoUser
+	roles    RepoRBAC
+	sessions RepoSession
+	revoked  *revocationCache
+	seen     *lastSeenTracker
+	mailer   Mailer
+	links    entity.MailLinks
+	ttl      entity.TokenTTL
+
+	// requireVerifiedEmail — без подтверждённой почты нельзя создавать задачи.
+	requireVerifiedEmail bool
+}
+
+func NewUserUseCase(
+	repo RepoUser,
+	roles RepoRBAC,
+	sessions RepoSession,
+	revoked RepoRevocation,
+	mailer Mailer,
+	links entity.MailLinks,
+	ttl entity.TokenTTL,
+	requireVerifiedEmail bool,
+) *UserUseCase {
+	return &UserUseCase{
+		repo:     repo,
+		roles:    roles,
+		sessions: sessions,
+		revoked:  newRevocationCache(revoked, sessions, ttl.Access),
+		seen:     newLastSeenTracker(),
+		mailer:   mailer,
+		links:    links,
+		ttl:      ttl,
+
+		requireVerifiedEmail: requireVerifiedEmail,
+	}
 }
 
-func NewUserUseCase(repo RepoUser) *UserUseCase {
-	return &UserUseCase{repo: repo}
+// normalizeEmail приводит почту к виду, в котором она хранится и ищется:
+// без пробелов по краям и в нижнем регистре.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
 
+// Register заводит пользователя и отправляет ему письмо для подтверждения почты.
 func (u *UserUseCase) Register(ctx context.Context, email, password, description string) (int64, error) {
+	email = normalizeEmail(email)
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return 0, fmt.Errorf("%w: некорректный email", ErrInvalidInput)
+	}
+	if err := validatePassword(password); err != nil {
+		return 0, err
+	}
 	existing, err := u.repo.GetByEmail(ctx, email)
 	if err != nil && err != sql.ErrNoRows {
 		return 0, err
@@ -38,23 +87,280 @@ func (u *UserUseCase) Register(ctx context.Context, email, password, description
 	if err != nil {
 		return 0, err
 	}
+	// аккаунт уже создан: если письмо не ушло, его можно запросить повторно
+	if err := u.sendVerification(ctx, id, email, time.Time{}); err != nil {
+		log.Printf("user %d: failed to start email verification: %v", id, err)
+	}
 	return id, nil
 }
 
-func (u *UserUseCase) Login(ctx context.Context, email, password string) (string, error) {
-	user, err := u.repo.GetByEmail(ctx, email)
+// Login проверяет пароль и начинает новую сессию; userAgent и ip
+// показываются пользователю в списке сессий.
+func (u *UserUseCase) Login(ctx context.Context, email, password, userAgent, ip string) (*entity.AuthTokens, error) {
+	user, err := u.repo.GetByEmail(ctx, normalizeEmail(email))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return "", errors.New("неверный email или пароль")
+			return nil, errors.New("неверный email или пароль")
 		}
-		return "", err
+		return nil, err
 	}
 	if !security.CheckPasswordHash(password, user.PasswordHash) {
-		return "", errors.New("неверный email или пароль")
+		return nil, errors.New("неверный email или пароль")
+	}
+	if err := checkActive(user); err != nil {
+		return nil, err
+	}
+
+	refresh, hash, err := security.NewToken()
+	if err != nil {
+		return nil, err
+	}
+	if r := []rune(userAgent); len(r) > maxUserAgentLen {
+		userAgent = string(r[:maxUserAgentLen])
+	}
+	refreshExpiresAt := time.Now().Add(u.ttl.Refresh)
+	session, err := u.sessions.Create(ctx, &entity.Session{
+		UserID:    user.ID,
+		UserAgent: userAgent,
+		IP:        ip,
+	}, hash, refreshExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return u.issue(ctx, user, session.ID, refresh, refreshExpiresAt)
+}
+
+// Refresh меняет refresh-токен на новую пару токенов той же сессии.
+// Старый токен становится недействительным; если его предъявят ещё раз,
+// сессия будет отозвана, и пользователю придётся войти заново.
+func (u *UserUseCase) Refresh(ctx context.Context, refreshToken string) (*entity.AuthTokens, error) {
+	refresh, hash, err := security.NewToken()
+	if err != nil {
+		return nil, err
+	}
+	refreshExpiresAt := time.Now().Add(u.ttl.Refresh)
+	session, ok, err := u.sessions.Rotate(ctx, security.HashToken(strings.TrimSpace(refreshToken)), hash, refreshExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRefreshTokenInvalid
+		}
+		return nil, err
 	}
-	token, err := security.GenerateJWT(user.ID, user.Email)
+	if !ok {
+		u.revoked.sessionRevoked(session.ID)
+		return nil, ErrRefreshTokenReused
+	}
+	u.seen.touch(session.ID, time.Now())
+
+	user, err := u.repo.GetByID(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRefreshTokenInvalid
+		}
+		return nil, err
+	}
+	if err := checkActive(user); err != nil {
+		return nil, err
+	}
+	return u.issue(ctx, user, session.ID, refresh, refreshExpiresAt)
+}
+
+// issue выписывает JWT сессии с текущими ролями пользователя в пару к уже
+// сохранённому refresh-токену.
+func (u *UserUseCase) issue(ctx context.Context, user *entity.User, sessionID int64, refresh string, refreshExpiresAt time.Time) (*entity.AuthTokens, error) {
+	grants, err := u.roles.UserRoles(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	roles := make([]string, len(grants))
+	for i, g := range grants {
+		roles[i] = g.Role
+	}
+	expiresAt := time.Now().Add(u.ttl.Access)
+	token, err := security.GenerateJWT(user.ID, user.Email, roles, sessionID, expiresAt)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	return &entity.AuthTokens{
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refresh,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// Logout отзывает токен доступа claims и завершает его сессию.
+func (u *UserUseCase) Logout(ctx context.Context, claims *security.Claims) error {
+	if claims.ID != "" && claims.ExpiresAt != nil {
+		err := u.revoked.revokeToken(ctx, entity.RevokedToken{
+			ID:        claims.ID,
+			UserID:    claims.UserID,
+			ExpiresAt: claims.ExpiresAt.Time,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if claims.SessionID == 0 {
+		return nil
+	}
+	if err := u.RevokeSession(ctx, claims.UserID, claims.SessionID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+		return err
+	}
+	return nil
+}
+
+// LogoutAll отзывает все токены пользователя на всех устройствах.
+func (u *UserUseCase) LogoutAll(ctx context.Context, userID int64) error {
+	if err := u.repo.RevokeTokens(ctx, userID, tokensCutoff()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// tokensCutoff — отсечка для отзыва всех токенов: выданные раньше неё не
+// принимаются. Округляется до точности времени в токене, чтобы токен,
+// выданный сразу после отзыва, не оказался «раньше» отсечки.
+func tokensCutoff() time.Time {
+	return time.Now().Truncate(security.TimePrecision)
+}
+
+// SyncRevocations подтягивает в память отзывы, сделанные другими
+// экземплярами сервиса. Вызывается при старте и планировщиком.
+func (u *UserUseCase) SyncRevocations(ctx context.Context) error {
+	return u.revoked.sync(ctx)
+}
+
+// PurgeExpiredTokens удаляет отработавшие сессии с их refresh-токенами и
+// отзывы истёкших JWT. Вызывается планировщиком.
+func (u *UserUseCase) PurgeExpiredTokens(ctx context.Context) error {
+	if err := u.sessions.DeleteExpired(ctx, time.Now().Add(-u.ttl.Access)); err != nil {
+		return err
+	}
+	return u.revoked.tokenRepo.DeleteExpired(ctx)
+}
+
+// checkActive отказывает отключённым аккаунтам и аккаунтам, ждущим сброса пароля.
+func checkActive(user *entity.User) error {
+	switch {
+	case user.DisabledAt != nil:
+		return ErrAccountDisabled
+	case user.MustResetPassword:
+		return ErrPasswordResetRequired
+	}
+	return nil
+}
+
+// Authenticate проверяет токен с уже проверенной подписью и возвращает его
+// владельца: ни токен, ни его сессия не отозваны, а владелец всё ещё может
+// работать — аккаунт существует, не отключён и не ждёт сброса пароля.
+// Заодно отмечает активность сессии.
+func (u *UserUseCase) Authenticate(ctx context.Context, claims *security.Claims) (*entity.User, error) {
+	if u.revoked.isRevoked(claims.ID, claims.SessionID) {
+		return nil, ErrTokenRevoked
+	}
+	user, err := u.repo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAccountDisabled
+		}
+		return nil, err
+	}
+	if err := checkActive(user); err != nil {
+		return nil, err
+	}
+	if user.TokensValidAfter != nil && (claims.IssuedAt == nil || claims.IssuedAt.Before(*user.TokensValidAfter)) {
+		return nil, ErrTokenRevoked
+	}
+	if claims.SessionID != 0 {
+		u.seen.touch(claims.SessionID, time.Now())
+	}
+	return user, nil
+}
+
+// minPasswordLen — минимальная длина нового пароля.
+const minPasswordLen = 8
+
+func validatePassword(password string) error {
+	if len([]rune(strings.TrimSpace(password))) < minPasswordLen {
+		return fmt.Errorf("%w: пароль должен быть не короче %d символов", ErrInvalidInput, minPasswordLen)
+	}
+	return nil
+}
+
+const (
+	// forgotPasswordTTL — сколько действует ссылка из письма о сбросе пароля.
+	forgotPasswordTTL = time.Hour
+	// forgotPasswordInterval — не чаще одного письма о сбросе за этот срок.
+	forgotPasswordInterval = time.Minute
+	// mailTimeout — сколько ждать отправки письма.
+	mailTimeout = time.Minute
+)
+
+// ForgotPassword отправляет на email ссылку для сброса пароля. Ответ не
+// зависит от того, есть ли такой аккаунт: письмо уходит в фоне, а для
+// неизвестной почты и отключённого аккаунта ничего не делается.
+func (u *UserUseCase) ForgotPassword(ctx context.Context, email string) error {
+	user, err := u.repo.GetByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if user.DisabledAt != nil {
+		return nil
+	}
+
+	token, hash, err := security.NewToken()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	created, err := u.repo.CreatePasswordReset(ctx, user.ID, hash, now.Add(forgotPasswordTTL), now.Add(-forgotPasswordInterval))
+	if err != nil || !created {
+		return err
+	}
+	u.sendAsync(ctx, entity.Email{
+		To:      user.Email,
+		Subject: "Сброс пароля",
+		Body: "Чтобы задать новый пароль, перейдите по ссылке:\n\n" +
+			u.links.PasswordReset + token + "\n\n" +
+			"Ссылка действует один час и только один раз. Если вы не запрашивали сброс, просто проигнорируйте это письмо.\n",
+	})
+	return nil
+}
+
+// sendAsync отправляет письмо в фоне, не дожидаясь SMTP-сервера: время
+// ответа не должно выдавать, ушло ли письмо.
+func (u *UserUseCase) sendAsync(ctx context.Context, msg entity.Email) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), mailTimeout)
+	go func() {
+		defer cancel()
+		if err := u.mailer.Send(ctx, msg); err != nil {
+			log.Printf("mail: failed to send %q: %v", msg.Subject, err)
+		}
+	}()
+}
+
+// ResetPassword задаёт новый пароль по одноразовому токену сброса. Все
+// выданные раньше токены пользователя перестают действовать.
+func (u *UserUseCase) ResetPassword(ctx context.Context, token, password string) error {
+	if err := validatePassword(password); err != nil {
+		return err
+	}
+	hash, err := security.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	if _, err := u.repo.ResetPassword(ctx, security.HashToken(strings.TrimSpace(token)), hash, tokensCutoff()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrResetTokenInvalid
+		}
+		return err
 	}
-	return token, nil
+	return nil
 }
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"context"
+	"fmt"
+)
+
+// Authorizer проверяет права пользователя на задачи и проекты. Репозитории
+// по-прежнему фильтруют по owner_id, поэтому после проверки usecase работает
+// от имени владельца ресурса, а не текущего пользователя.
+type Authorizer struct {
+	shares   RepoShare
+	tasks    RepoTask
+	projects RepoProject
+}
+
+func NewAuthorizer(shares RepoShare, tasks RepoTask, projects RepoProject) *Authorizer {
+	return &Authorizer{shares: shares, tasks: tasks, projects: projects}
+}
+
+// Task возвращает задачу, если у userID есть права не ниже min. Без доступа
+// задача для пользователя не существует (sql.ErrNoRows); с доступом ниже
+// нужного — ErrForbidden.
+func (a *Authorizer) Task(ctx context.Context, taskID, userID int64, min entity.Role) (*entity.Task, error) {
+	ownerID, err := a.TaskOwner(ctx, taskID, userID, min)
+	if err != nil {
+		return nil, err
+	}
+	return a.tasks.GetByID(ctx, taskID, ownerID)
+}
+
+// TaskOwner — то же, что Task, но возвращает только владельца задачи.
+func (a *Authorizer) TaskOwner(ctx context.Context, taskID, userID int64, min entity.Role) (int64, error) {
+	role, ownerID, err := a.shares.TaskRole(ctx, taskID, userID)
+	if err != nil {
+		return 0, err
+	}
+	if !role.Allows(min) {
+		return 0, fmt.Errorf("%w: нужна роль %s, у вас %s", ErrForbidden, min, role)
+	}
+	return ownerID, nil
+}
+
+// TaskRole возвращает задачу и роль в ней пользователя — для проверок,
+// которые зависят не только от роли.
+func (a *Authorizer) TaskRole(ctx context.Context, taskID, userID int64) (*entity.Task, entity.Role, error) {
+	role, ownerID, err := a.shares.TaskRole(ctx, taskID, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	task, err := a.tasks.GetByID(ctx, taskID, ownerID)
+	if err != nil {
+		return nil, "", err
+	}
+	return task, role, nil
+}
+
+// Project — то же, что Task, для проекта.
+func (a *Authorizer) Project(ctx context.Context, projectID, userID int64, min entity.Role) (*entity.Project, error) {
+	role, ownerID, err := a.shares.ProjectRole(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !role.Allows(min) {
+		return nil, fmt.Errorf("%w: нужна роль %s, у вас %s", ErrForbidden, min, role)
+	}
+	return a.projects.GetByID(ctx, projectID, ownerID)
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxChecklistText = 500
+
+type ChecklistUseCase struct {
+	repo   RepoChecklist
+	access *Authorizer
+}
+
+func NewChecklistUseCase(repo RepoChecklist, access *Authorizer) *ChecklistUseCase {
+	return &ChecklistUseCase{repo: repo, access: access}
+}
+
+func normalizeChecklistText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || utf8.RuneCountInString(text) > maxChecklistText {
+		return "", fmt.Errorf("%w: текст пункта должен быть от 1 до %d символов", ErrInvalidInput, maxChecklistText)
+	}
+	return text, nil
+}
+
+func (u *ChecklistUseCase) ListItems(ctx context.Context, taskID, userID int64) ([]*entity.ChecklistItem, error) {
+	if _, err := u.access.TaskOwner(ctx, taskID, userID, entity.RoleViewer); err != nil {
+		return nil, err
+	}
+	return u.repo.List(ctx, taskID)
+}
+
+// AddItem добавляет пункт; position (с нуля) — куда вставить, nil — в конец.
+func (u *ChecklistUseCase) AddItem(ctx context.Context, taskID, userID int64, text string, position *int) (*entity.ChecklistItem, error) {
+	text, err := normalizeChecklistText(text)
+	if err != nil {
+		return nil, err
+	}
+	if position != nil && *position < 0 {
+		return nil, fmt.Errorf("%w: position не может быть отрицательной", ErrInvalidInput)
+	}
+	if _, err := u.access.TaskOwner(ctx, taskID, userID, entity.RoleEditor); err != nil {
+		return nil, err
+	}
+	return u.repo.Add(ctx, taskID, text, position)
+}
+
+// UpdateItem меняет текст и/или отметку; nil — не менять.
+func (u *ChecklistUseCase) UpdateItem(ctx context.Context, id, taskID, userID int64, text *string, done *bool) (*entity.ChecklistItem, error) {
+	if _, err := u.access.TaskOwner(ctx, taskID, userID, entity.RoleEditor); err != nil {
+		return nil, err
+	}
+	item, err := u.repo.GetByID(ctx, id, taskID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrChecklistItemNotFound
+		}
+		return nil, err
+	}
+	if text != nil {
+		if item.Text, err = normalizeChecklistText(*text); err != nil {
+			return nil, err
+		}
+	}
+	if done != nil {
+		item.Done = *done
+	}
+	return u.repo.Update(ctx, item)
+}
+
+func (u *ChecklistUseCase) DeleteItem(ctx context.Context, id, taskID, userID int64) error {
+	if _, err := u.access.TaskOwner(ctx, taskID, userID, entity.RoleEditor); err != nil {
+		return err
+	}
+	if err := u.repo.Delete(ctx, id, taskID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrChecklistItemNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Reorder задаёт новый порядок пунктов; ids — все пункты чек-листа.
+func (u *ChecklistUseCase) Reorder(ctx context.Context, taskID, userID int64, ids []int64) ([]*entity.ChecklistItem, error) {
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return nil, fmt.Errorf("%w: пункт %d указан дважды", ErrInvalidInput, id)
+		}
+		seen[id] = true
+	}
+	if _, err := u.access.TaskOwner(ctx, taskID, userID, entity.RoleEditor); err != nil {
+		return nil, err
+	}
+	ok, err := u.repo.Reorder(ctx, taskID, ids)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrChecklistConflict
+	}
+	return u.repo.List(ctx, taskID)
+}
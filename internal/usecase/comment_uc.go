@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"app/internal/markdown"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxCommentLength = 10000
+
+type CommentUseCase struct {
+	repo   RepoComment
+	access *Authorizer
+}
+
+func NewCommentUseCase(repo RepoComment, access *Authorizer) *CommentUseCase {
+	return &CommentUseCase{repo: repo, access: access}
+}
+
+func normalizeCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > maxCommentLength {
+		return "", fmt.Errorf("%w: комментарий должен быть от 1 до %d символов", ErrInvalidInput, maxCommentLength)
+	}
+	return body, nil
+}
+
+func render(comments ...*entity.Comment) {
+	for _, c := range comments {
+		c.BodyHTML = markdown.Render(c.Body)
+	}
+}
+
+// getComment находит комментарий к задаче, на которую у пользователя есть
+// права не ниже min.
+func (u *CommentUseCase) getComment(ctx context.Context, id, taskID, userID int64, min entity.Role) (*entity.Comment, error) {
+	if _, err := u.access.TaskOwner(ctx, taskID, userID, min); err != nil {
+		return nil, err
+	}
+	comment, err := u.repo.GetByID(ctx, id, taskID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (u *CommentUseCase) CreateComment(ctx context.Context, taskID, userID int64, body string) (*entity.Comment, error) {
+	body, err := normalizeCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.access.TaskOwner(ctx, taskID, userID, entity.RoleEditor); err != nil {
+		return nil, err
+	}
+	comment, err := u.repo.Create(ctx, &entity.Comment{TaskID: taskID, AuthorID: userID, Body: body})
+	if err != nil {
+		return nil, err
+	}
+	render(comment)
+	return comment, nil
+}
+
+func (u *CommentUseCase) ListComments(ctx context.Context, taskID, userID int64) ([]*entity.Comment, error) {
+	if _, err := u.access.TaskOwner(ctx, taskID, userID, entity.RoleViewer); err != nil {
+		return nil, err
+	}
+	comments, err := u.repo.ListByTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	render(comments...)
+	return comments, nil
+}
+
+// UpdateComment меняет текст; редактировать можно только свои комментарии.
+func (u *CommentUseCase) UpdateComment(ctx context.Context, id, taskID, userID int64, body string) (*entity.Comment, error) {
+	body, err := normalizeCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := u.getComment(ctx, id, taskID, userID, entity.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != userID {
+		return nil, fmt.Errorf("%w: редактировать можно только свои комментарии", ErrForbidden)
+	}
+	comment.Body = body
+	comment, err = u.repo.Update(ctx, comment)
+	if err != nil {
+		return nil, err
+	}
+	render(comment)
+	return comment, nil
+}
+
+// DeleteComment удаляет комментарий автора; с ролью owner можно удалить любой.
+func (u *CommentUseCase) DeleteComment(ctx context.Context, id, taskID, userID int64) error {
+	comment, err := u.getComment(ctx, id, taskID, userID, entity.RoleViewer)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID != userID {
+		if _, err := u.access.TaskOwner(ctx, taskID, userID, entity.RoleOwner); err != nil {
+			if errors.Is(err, ErrForbidden) {
+				return fmt.Errorf("%w: удалить можно только свой комментарий", ErrForbidden)
+			}
+			return err
+		}
+	}
+	return u.repo.Delete(ctx, id, taskID)
+}
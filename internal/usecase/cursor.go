@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// cursorToken — содержимое непрозрачного курсора. Sort хранит порядок, для
+// которого курсор выдан: с другим порядком он не имеет смысла.
+type cursorToken struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+}
+
+func sortSignature(keys []entity.TaskSort) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		if k.Desc {
+			parts[i] = "-" + string(k.Field)
+		} else {
+			parts[i] = string(k.Field)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// sortValue достаёт из задачи значение ключа сортировки.
+func sortValue(t *entity.Task, field entity.TaskSortField) any {
+	switch field {
+	case entity.SortByTitle:
+		return t.Title
+	case entity.SortByPriority:
+		return int64(t.Priority)
+	case entity.SortByStartAt:
+		return t.StartAt
+	case entity.SortByDueAt:
+		return t.DueAt
+	case entity.SortByCreatedAt:
+		return t.CreatedAt
+	case entity.SortByUpdatedAt:
+		return t.UpdatedAt
+	default:
+		return t.ID
+	}
+}
+
+func encodeCursor(keys []entity.TaskSort, last *entity.Task) (string, error) {
+	tok := cursorToken{Sort: sortSignature(keys)}
+	for _, k := range keys {
+		raw, err := json.Marshal(sortValue(last, k.Field))
+		if err != nil {
+			return "", err
+		}
+		tok.Values = append(tok.Values, raw)
+	}
+	data, err := json.Marshal(tok)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(keys []entity.TaskSort, s string) (*entity.TaskCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var tok cursorToken
+	if err := json.Unmarshal(data, &tok); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if tok.Sort != sortSignature(keys) || len(tok.Values) != len(keys) {
+		return nil, fmt.Errorf("%w: курсор выдан для другой сортировки", ErrInvalidCursor)
+	}
+
+	cur := &entity.TaskCursor{Values: make([]any, len(keys))}
+	for i, k := range keys {
+		var err error
+		switch k.Field {
+		case entity.SortByTitle:
+			var v string
+			err = json.Unmarshal(tok.Values[i], &v)
+			cur.Values[i] = v
+		case entity.SortByStartAt, entity.SortByDueAt:
+			var v *time.Time
+			err = json.Unmarshal(tok.Values[i], &v)
+			cur.Values[i] = v
+		case entity.SortByCreatedAt, entity.SortByUpdatedAt:
+			var v time.Time
+			err = json.Unmarshal(tok.Values[i], &v)
+			cur.Values[i] = v
+		default:
+			var v int64
+			err = json.Unmarshal(tok.Values[i], &v)
+			cur.Values[i] = v
+		}
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+	return cur, nil
+}
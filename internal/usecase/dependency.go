@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// AddDependency помечает задачу taskID заблокированной задачей dependsOnID.
+// Обе задачи должны принадлежать одному владельцу.
+func (t *TaskUseCase) AddDependency(ctx context.Context, taskID, dependsOnID, userID int64) (*entity.DependencyGraph, error) {
+	if taskID == dependsOnID {
+		return nil, ErrDependencyCycle
+	}
+	ownerID, err := t.access.TaskOwner(ctx, taskID, userID, entity.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	// блокирующая задача должна быть хотя бы видна пользователю
+	if _, err := t.access.TaskOwner(ctx, dependsOnID, userID, entity.RoleViewer); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDependencyMissing
+		}
+		return nil, err
+	}
+	ok, err := t.repo.AddDependency(ctx, taskID, dependsOnID, ownerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: задачи принадлежат разным владельцам", ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrDependencyCycle
+	}
+	return t.DependencyGraph(ctx, taskID, userID)
+}
+
+func (t *TaskUseCase) RemoveDependency(ctx context.Context, taskID, dependsOnID, userID int64) (*entity.DependencyGraph, error) {
+	ownerID, err := t.access.TaskOwner(ctx, taskID, userID, entity.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.repo.RemoveDependency(ctx, taskID, dependsOnID, ownerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDependencyMissing
+		}
+		return nil, err
+	}
+	return t.DependencyGraph(ctx, taskID, userID)
+}
+
+// DependencyGraph возвращает граф зависимостей задачи с топологическим порядком.
+func (t *TaskUseCase) DependencyGraph(ctx context.Context, taskID, userID int64) (*entity.DependencyGraph, error) {
+	ownerID, err := t.access.TaskOwner(ctx, taskID, userID, entity.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := t.repo.DependencyGraph(ctx, taskID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	graph.Order = topoOrder(graph.Tasks, graph.Edges)
+	return graph, nil
+}
+
+func (t *TaskUseCase) checkBlockers(ctx context.Context, task *entity.Task, wf *entity.Workflow) error {
+	open, err := t.repo.BlockersIn(ctx, task.ID, task.OwnerID, openStatuses(wf))
+	if err != nil {
+		return err
+	}
+	if len(open) > 0 {
+		return fmt.Errorf("%w: %v", ErrOpenBlockers, open)
+	}
+	return nil
+}
+
+// topoOrder сортирует задачи алгоритмом Кана: блокирующие идут раньше
+// заблокированных, среди готовых — по возрастанию id, чтобы порядок был
+// стабильным. Циклы AddDependency не допускает; если они всё же есть
+// в данных, оставшиеся задачи дописываются в конец.
+func topoOrder(tasks []*entity.Task, edges []entity.DependencyEdge) []int64 {
+	indegree := make(map[int64]int, len(tasks))
+	for _, task := range tasks {
+		indegree[task.ID] = 0
+	}
+	next := make(map[int64][]int64)
+	for _, e := range edges {
+		if _, ok := indegree[e.TaskID]; !ok {
+			continue
+		}
+		if _, ok := indegree[e.DependsOnID]; !ok {
+			continue
+		}
+		indegree[e.TaskID]++
+		next[e.DependsOnID] = append(next[e.DependsOnID], e.TaskID)
+	}
+
+	var ready []int64
+	for id, n := range indegree {
+		if n == 0 {
+			ready = append(ready, id)
+		}
+	}
+	order := make([]int64, 0, len(tasks))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return ready[i] < ready[j] })
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, id)
+		delete(indegree, id)
+		for _, n := range next[id] {
+			indegree[n]--
+			if indegree[n] == 0 {
+				ready = append(ready, n)
+			}
+		}
+	}
+
+	var rest []int64
+	for id := range indegree {
+		rest = append(rest, id)
+	}
+	sort.Slice(rest, func(i, j int) bool { return rest[i] < rest[j] })
+	return append(order, rest...)
+}
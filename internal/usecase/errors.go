@@ -0,0 +1,50 @@
+package usecase
+
+import "errors"
+
+var (
+	ErrInvalidInput          = errors.New("некорректные данные")
+	ErrUnknownStatus         = errors.New("неизвестный статус")
+	ErrInvalidTransition     = errors.New("недопустимый переход статуса")
+	ErrStateExists           = errors.New("такой статус уже существует")
+	ErrStateInUse            = errors.New("статус используется задачами")
+	ErrInvalidCursor         = errors.New("некорректный курсор")
+	ErrInvalidFilter         = errors.New("некорректный фильтр")
+	ErrLabelExists           = errors.New("метка с таким именем уже существует")
+	ErrLabelNotFound         = errors.New("метка не найдена")
+	ErrProjectNotFound       = errors.New("проект не найден")
+	ErrParentNotFound        = errors.New("родительская задача не найдена")
+	ErrSubtaskCycle          = errors.New("задача не может стать подзадачей самой себя или своего потомка")
+	ErrOpenSubtasks          = errors.New("у задачи есть невыполненные подзадачи")
+	ErrHasSubtasks           = errors.New("у задачи есть подзадачи")
+	ErrSubtasksChanged       = errors.New("подзадачи изменились, повторите запрос")
+	ErrDependencyCycle       = errors.New("зависимость образует цикл")
+	ErrDependencyMissing     = errors.New("зависимость не найдена")
+	ErrOpenBlockers          = errors.New("задачу блокируют невыполненные задачи")
+	ErrInvalidRecurrence     = errors.New("некорректное правило повторения")
+	ErrReminderNotFound      = errors.New("напоминание не найдено")
+	ErrCommentNotFound       = errors.New("комментарий не найден")
+	ErrForbidden             = errors.New("недостаточно прав")
+	ErrAttachmentNotFound    = errors.New("вложение не найдено")
+	ErrFileTooLarge          = errors.New("файл слишком большой")
+	ErrFileType              = errors.New("недопустимый тип файла")
+	ErrChecklistItemNotFound = errors.New("пункт чек-листа не найден")
+	ErrChecklistConflict     = errors.New("чек-лист изменился, обновите список и повторите")
+	ErrUserNotFound          = errors.New("пользователь не найден")
+	ErrShareNotFound         = errors.New("доступ не найден")
+	ErrMemberNotFound        = errors.New("участник не найден")
+	ErrLastAdmin             = errors.New("в пространстве должен остаться администратор")
+	ErrRoleNotFound          = errors.New("роль не найдена")
+	ErrLastSystemAdmin       = errors.New("в системе должен остаться хотя бы один администратор")
+	ErrAccountDisabled       = errors.New("аккаунт отключён")
+	ErrPasswordResetRequired = errors.New("нужно сбросить пароль")
+	ErrResetTokenInvalid     = errors.New("ссылка для сброса пароля недействительна или устарела")
+	ErrRefreshTokenInvalid   = errors.New("refresh-токен недействителен или истёк")
+	ErrRefreshTokenReused    = errors.New("refresh-токен уже использован, войдите заново")
+	ErrTokenRevoked          = errors.New("токен отозван, войдите заново")
+	ErrSessionNotFound       = errors.New("сессия не найдена")
+	ErrEmailNotVerified      = errors.New("подтвердите почту, чтобы продолжить")
+	ErrEmailAlreadyVerified  = errors.New("почта уже подтверждена")
+	ErrVerifyTokenInvalid    = errors.New("ссылка для подтверждения почты недействительна или устарела")
+	ErrInvitationNotFound    = errors.New("приглашение не найдено, истекло или выписано на другую почту")
+)
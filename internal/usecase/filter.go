@@ -0,0 +1,456 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Язык фильтров списка задач:
+//
+//	status:open AND priority>=high AND tag:backend AND created_at>2026-01-01
+//	(title:"отчёт" OR description:отчёт) AND NOT status:cancelled
+//
+// Условие — «поле оператор значение», операторы : = != < <= > >=.
+// Для текстовых полей «:» означает поиск подстроки, для остальных — равенство;
+// label (или tag) проверяет наличие метки с таким именем.
+// Условия объединяются AND, OR, NOT и скобками; AND можно опускать.
+// Даты — YYYY-MM-DD (сутки по UTC) или RFC3339.
+
+const (
+	maxFilterLength = 1000
+	maxFilterDepth  = 32
+)
+
+// FilterError указывает на место в выражении, где разбор не удался.
+type FilterError struct {
+	Pos   int    // позиция токена, с 1, в символах
+	Token string // сам токен
+	Msg   string
+}
+
+func (e *FilterError) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("ошибка в фильтре на позиции %d: %s", e.Pos, e.Msg)
+	}
+	return fmt.Sprintf("ошибка в фильтре на позиции %d (%q): %s", e.Pos, e.Token, e.Msg)
+}
+
+func (e *FilterError) Unwrap() error { return ErrInvalidFilter }
+
+type filterFieldKind int
+
+const (
+	kindInt filterFieldKind = iota
+	kindText
+	kindStatus
+	kindPriority
+	kindTime
+	kindLabel
+)
+
+var filterFields = map[string]struct {
+	field entity.FilterField
+	kind  filterFieldKind
+}{
+	"id":          {entity.FilterID, kindInt},
+	"project_id":  {entity.FilterProjectID, kindInt},
+	"parent_id":   {entity.FilterParentID, kindInt},
+	"title":       {entity.FilterTitle, kindText},
+	"description": {entity.FilterDescription, kindText},
+	"status":      {entity.FilterStatus, kindStatus},
+	"priority":    {entity.FilterPriority, kindPriority},
+	"start_at":    {entity.FilterStartAt, kindTime},
+	"due_at":      {entity.FilterDueAt, kindTime},
+	"created_at":  {entity.FilterCreatedAt, kindTime},
+	"updated_at":  {entity.FilterUpdatedAt, kindTime},
+	"label":       {entity.FilterLabel, kindLabel},
+	"tag":         {entity.FilterLabel, kindLabel},
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokBad // незакрытая кавычка
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type filterParser struct {
+	src    []rune
+	i      int
+	peeked *token
+	depth  int
+	wf     *entity.Workflow
+}
+
+// parseFilter разбирает выражение в дерево. «open» и «closed» проверяют
+// категорию статуса по workflow владельца каждой задачи. Прочие статусы
+// сверяются с wf; nil — список охватывает задачи разных владельцев, и
+// статус проверяется только на формат ключа.
+func parseFilter(src string, wf *entity.Workflow) (entity.FilterNode, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, nil
+	}
+	if len([]rune(src)) > maxFilterLength {
+		return nil, &FilterError{Pos: maxFilterLength, Msg: "слишком длинное выражение"}
+	}
+	p := &filterParser{src: []rune(src), wf: wf}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.next(); tok.kind != tokEOF {
+		return nil, p.errorAt(tok, "лишний токен")
+	}
+	return node, nil
+}
+
+func (p *filterParser) errorAt(tok token, msg string) error {
+	switch tok.kind {
+	case tokEOF:
+		return &FilterError{Pos: tok.pos, Msg: "неожиданный конец выражения: " + msg}
+	case tokBad:
+		return &FilterError{Pos: tok.pos, Token: tok.text, Msg: "незакрытая кавычка"}
+	}
+	return &FilterError{Pos: tok.pos, Token: tok.text, Msg: msg}
+}
+
+func (p *filterParser) skipSpaces() {
+	for p.i < len(p.src) && unicode.IsSpace(p.src[p.i]) {
+		p.i++
+	}
+}
+
+func isWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && !strings.ContainsRune(`()"<>=!:`, r)
+}
+
+func (p *filterParser) scan() token {
+	p.skipSpaces()
+	start := p.i
+	if p.i >= len(p.src) {
+		return token{kind: tokEOF, pos: start + 1}
+	}
+	r := p.src[p.i]
+	switch {
+	case r == '(':
+		p.i++
+		return token{kind: tokLParen, text: "(", pos: start + 1}
+	case r == ')':
+		p.i++
+		return token{kind: tokRParen, text: ")", pos: start + 1}
+	case r == '"':
+		return p.scanString()
+	case strings.ContainsRune("<>=!:", r):
+		p.i++
+		if (r == '<' || r == '>' || r == '!') && p.i < len(p.src) && p.src[p.i] == '=' {
+			p.i++
+		}
+		return token{kind: tokOp, text: string(p.src[start:p.i]), pos: start + 1}
+	default:
+		for p.i < len(p.src) && isWordRune(p.src[p.i]) {
+			p.i++
+		}
+		return token{kind: tokWord, text: string(p.src[start:p.i]), pos: start + 1}
+	}
+}
+
+func (p *filterParser) scanString() token {
+	start := p.i
+	p.i++ // открывающая кавычка
+	var b strings.Builder
+	for p.i < len(p.src) {
+		r := p.src[p.i]
+		p.i++
+		switch {
+		case r == '\\' && p.i < len(p.src):
+			b.WriteRune(p.src[p.i])
+			p.i++
+		case r == '"':
+			return token{kind: tokString, text: b.String(), pos: start + 1}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return token{kind: tokBad, text: string(p.src[start:]), pos: start + 1}
+}
+
+// scanValue читает значение после оператора. Значение без кавычек тянется до
+// пробела или скобки, поэтому в нём допустимы «:» и «-» (даты RFC3339).
+func (p *filterParser) scanValue() token {
+	p.skipSpaces()
+	start := p.i
+	if p.i < len(p.src) && p.src[p.i] == '"' {
+		return p.scanString()
+	}
+	for p.i < len(p.src) && !unicode.IsSpace(p.src[p.i]) && p.src[p.i] != '(' && p.src[p.i] != ')' {
+		p.i++
+	}
+	if p.i == start {
+		return token{kind: tokEOF, pos: start + 1}
+	}
+	return token{kind: tokWord, text: string(p.src[start:p.i]), pos: start + 1}
+}
+
+func (p *filterParser) peek() token {
+	if p.peeked == nil {
+		tok := p.scan()
+		p.peeked = &tok
+	}
+	return *p.peeked
+}
+
+func (p *filterParser) next() token {
+	tok := p.peek()
+	p.peeked = nil
+	return tok
+}
+
+func isKeyword(tok token, kw string) bool {
+	return tok.kind == tokWord && strings.EqualFold(tok.text, kw)
+}
+
+func (p *filterParser) parseOr() (entity.FilterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for isKeyword(p.peek(), "OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = entity.FilterOr{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (entity.FilterNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		switch {
+		case isKeyword(tok, "AND"):
+			p.next()
+		case tok.kind == tokWord && !isKeyword(tok, "OR"), tok.kind == tokLParen:
+			// AND по умолчанию: «status:open priority:high»
+		default:
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = entity.FilterAnd{Left: left, Right: right}
+	}
+}
+
+func (p *filterParser) parseUnary() (entity.FilterNode, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	tok := p.peek()
+	if p.depth > maxFilterDepth {
+		return nil, p.errorAt(tok, "слишком глубокая вложенность")
+	}
+
+	switch {
+	case isKeyword(tok, "NOT"):
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return entity.FilterNot{Expr: expr}, nil
+	case tok.kind == tokLParen:
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, p.errorAt(closing, "ожидалась «)»")
+		}
+		return expr, nil
+	default:
+		return p.parseCond()
+	}
+}
+
+func (p *filterParser) parseCond() (entity.FilterNode, error) {
+	fieldTok := p.next()
+	if fieldTok.kind != tokWord {
+		return nil, p.errorAt(fieldTok, "ожидалось имя поля")
+	}
+	spec, ok := filterFields[strings.ToLower(fieldTok.text)]
+	if !ok {
+		return nil, p.errorAt(fieldTok, "неизвестное поле")
+	}
+	opTok := p.next()
+	if opTok.kind != tokOp {
+		return nil, p.errorAt(opTok, "ожидался оператор (: = != < <= > >=)")
+	}
+	valTok := p.scanValue()
+	if valTok.kind != tokWord && valTok.kind != tokString {
+		return nil, p.errorAt(valTok, "ожидалось значение")
+	}
+
+	switch spec.kind {
+	case kindInt:
+		return p.intCond(spec.field, opTok, valTok)
+	case kindText:
+		return p.textCond(spec.field, opTok, valTok)
+	case kindStatus:
+		return p.statusCond(spec.field, opTok, valTok)
+	case kindPriority:
+		return p.priorityCond(spec.field, opTok, valTok)
+	case kindLabel:
+		return p.labelCond(spec.field, opTok, valTok)
+	default:
+		return p.timeCond(spec.field, opTok, valTok)
+	}
+}
+
+// compareOp переводит оператор сравнения; «:» — синоним «=».
+func compareOp(op string) (entity.FilterOp, bool) {
+	switch op {
+	case ":", "=":
+		return entity.OpEq, true
+	case "!=":
+		return entity.OpNe, true
+	case "<":
+		return entity.OpLt, true
+	case "<=":
+		return entity.OpLe, true
+	case ">":
+		return entity.OpGt, true
+	case ">=":
+		return entity.OpGe, true
+	}
+	return "", false
+}
+
+func (p *filterParser) intCond(field entity.FilterField, opTok, valTok token) (entity.FilterNode, error) {
+	op, ok := compareOp(opTok.text)
+	if !ok {
+		return nil, p.errorAt(opTok, "некорректный оператор")
+	}
+	v, err := strconv.ParseInt(valTok.text, 10, 64)
+	if err != nil {
+		return nil, p.errorAt(valTok, "ожидалось целое число")
+	}
+	return entity.FilterCond{Field: field, Op: op, Value: v}, nil
+}
+
+func (p *filterParser) textCond(field entity.FilterField, opTok, valTok token) (entity.FilterNode, error) {
+	switch opTok.text {
+	case ":":
+		return entity.FilterCond{Field: field, Op: entity.OpContains, Value: valTok.text}, nil
+	case "=":
+		return entity.FilterCond{Field: field, Op: entity.OpEq, Value: valTok.text}, nil
+	case "!=":
+		return entity.FilterCond{Field: field, Op: entity.OpNe, Value: valTok.text}, nil
+	}
+	return nil, p.errorAt(opTok, "для текста допустимы только : = !=")
+}
+
+func (p *filterParser) statusCond(field entity.FilterField, opTok, valTok token) (entity.FilterNode, error) {
+	if opTok.text != ":" && opTok.text != "=" && opTok.text != "!=" {
+		return nil, p.errorAt(opTok, "для статуса допустимы только : = !=")
+	}
+
+	var node entity.FilterNode
+	switch valTok.text {
+	case "open":
+		node = entity.FilterCond{Field: entity.FilterStatusCategory, Op: entity.OpEq, Value: entity.CategoryOpen}
+	case "closed":
+		node = entity.FilterCond{Field: entity.FilterStatusCategory, Op: entity.OpNe, Value: entity.CategoryOpen}
+	default:
+		key := entity.TaskStatus(valTok.text)
+		if p.wf != nil {
+			if _, ok := p.wf.State(key); !ok {
+				return nil, p.errorAt(valTok, "неизвестный статус")
+			}
+		} else if !stateKeyRe.MatchString(valTok.text) {
+			return nil, p.errorAt(valTok, "некорректный статус")
+		}
+		node = entity.FilterCond{Field: field, Op: entity.OpIn, Value: []entity.TaskStatus{key}}
+	}
+	if opTok.text == "!=" {
+		node = entity.FilterNot{Expr: node}
+	}
+	return node, nil
+}
+
+func (p *filterParser) labelCond(field entity.FilterField, opTok, valTok token) (entity.FilterNode, error) {
+	switch opTok.text {
+	case ":", "=":
+		return entity.FilterCond{Field: field, Op: entity.OpEq, Value: valTok.text}, nil
+	case "!=":
+		return entity.FilterCond{Field: field, Op: entity.OpNe, Value: valTok.text}, nil
+	}
+	return nil, p.errorAt(opTok, "для метки допустимы только : = !=")
+}
+
+func (p *filterParser) priorityCond(field entity.FilterField, opTok, valTok token) (entity.FilterNode, error) {
+	op, ok := compareOp(opTok.text)
+	if !ok {
+		return nil, p.errorAt(opTok, "некорректный оператор")
+	}
+	v, err := entity.ParsePriority(strings.ToLower(valTok.text))
+	if err != nil {
+		return nil, p.errorAt(valTok, "приоритет должен быть low, medium, high или urgent")
+	}
+	return entity.FilterCond{Field: field, Op: op, Value: v}, nil
+}
+
+func (p *filterParser) timeCond(field entity.FilterField, opTok, valTok token) (entity.FilterNode, error) {
+	op, ok := compareOp(opTok.text)
+	if !ok {
+		return nil, p.errorAt(opTok, "некорректный оператор")
+	}
+	if t, err := time.Parse(time.RFC3339, valTok.text); err == nil {
+		return entity.FilterCond{Field: field, Op: op, Value: t}, nil
+	}
+	day, err := time.Parse("2006-01-02", valTok.text)
+	if err != nil {
+		return nil, p.errorAt(valTok, "ожидалась дата YYYY-MM-DD или RFC3339")
+	}
+
+	// дата без времени — это целые сутки
+	next := day.AddDate(0, 0, 1)
+	switch op {
+	case entity.OpEq, entity.OpNe:
+		var node entity.FilterNode = entity.FilterAnd{
+			Left:  entity.FilterCond{Field: field, Op: entity.OpGe, Value: day},
+			Right: entity.FilterCond{Field: field, Op: entity.OpLt, Value: next},
+		}
+		if op == entity.OpNe {
+			node = entity.FilterNot{Expr: node}
+		}
+		return node, nil
+	case entity.OpGt:
+		return entity.FilterCond{Field: field, Op: entity.OpGe, Value: next}, nil
+	case entity.OpLe:
+		return entity.FilterCond{Field: field, Op: entity.OpLt, Value: next}, nil
+	default:
+		return entity.FilterCond{Field: field, Op: op, Value: day}, nil
+	}
+}
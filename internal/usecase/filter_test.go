@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testWorkflow — встроенные состояния плюс пользовательские review (open)
+// и archived (cancelled).
+func testWorkflow() *entity.Workflow {
+	return entity.NewWorkflow([]entity.WorkflowState{
+		{Key: "review", Name: "Ревью", Category: entity.CategoryOpen, Custom: true},
+		{Key: "archived", Name: "Архив", Category: entity.CategoryCancelled, Custom: true},
+	}, nil)
+}
+
+func cond(field entity.FilterField, op entity.FilterOp, v any) entity.FilterCond {
+	return entity.FilterCond{Field: field, Op: op, Value: v}
+}
+
+func day(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseFilter(t *testing.T) {
+	var (
+		high    = cond(entity.FilterPriority, entity.OpEq, entity.PriorityHigh)
+		backend = cond(entity.FilterLabel, entity.OpEq, "backend")
+	)
+	tests := []struct {
+		src  string
+		want entity.FilterNode
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"id=5", cond(entity.FilterID, entity.OpEq, int64(5))},
+		{"project_id != 7", cond(entity.FilterProjectID, entity.OpNe, int64(7))},
+		{"title:отчёт", cond(entity.FilterTitle, entity.OpContains, "отчёт")},
+		{`title="квартальный отчёт"`, cond(entity.FilterTitle, entity.OpEq, "квартальный отчёт")},
+		{`description:"say \"hi\""`, cond(entity.FilterDescription, entity.OpContains, `say "hi"`)},
+		{"priority>=HIGH", cond(entity.FilterPriority, entity.OpGe, entity.PriorityHigh)},
+		{"tag:backend", backend},
+		{"label!=backend", cond(entity.FilterLabel, entity.OpNe, "backend")},
+		{"STATUS:review", cond(entity.FilterStatus, entity.OpIn, []entity.TaskStatus{"review"})},
+		{"status:open", cond(entity.FilterStatusCategory, entity.OpEq, entity.CategoryOpen)},
+		{"status=closed", cond(entity.FilterStatusCategory, entity.OpNe, entity.CategoryOpen)},
+		{"status!=open", entity.FilterNot{Expr: cond(entity.FilterStatusCategory, entity.OpEq, entity.CategoryOpen)}},
+		{"status!=done", entity.FilterNot{Expr: cond(entity.FilterStatus, entity.OpIn, []entity.TaskStatus{entity.StatusDone})}},
+
+		// даты: дата без времени — целые сутки по UTC
+		{"due_at=2026-01-01", entity.FilterAnd{
+			Left:  cond(entity.FilterDueAt, entity.OpGe, day("2026-01-01")),
+			Right: cond(entity.FilterDueAt, entity.OpLt, day("2026-01-02")),
+		}},
+		{"due_at!=2026-01-01", entity.FilterNot{Expr: entity.FilterAnd{
+			Left:  cond(entity.FilterDueAt, entity.OpGe, day("2026-01-01")),
+			Right: cond(entity.FilterDueAt, entity.OpLt, day("2026-01-02")),
+		}}},
+		{"created_at>2026-12-31", cond(entity.FilterCreatedAt, entity.OpGe, day("2027-01-01"))},
+		{"created_at<=2026-02-28", cond(entity.FilterCreatedAt, entity.OpLt, day("2026-03-01"))},
+		{"start_at<2026-05-01", cond(entity.FilterStartAt, entity.OpLt, day("2026-05-01"))},
+		{"updated_at>=2026-05-01T10:30:00+03:00", cond(entity.FilterUpdatedAt, entity.OpGe,
+			time.Date(2026, 5, 1, 10, 30, 0, 0, time.FixedZone("", 3*3600)))},
+
+		// AND связывает сильнее OR, AND можно опускать
+		{"priority:high tag:backend", entity.FilterAnd{Left: high, Right: backend}},
+		{"id=1 OR priority:high AND tag:backend", entity.FilterOr{
+			Left:  cond(entity.FilterID, entity.OpEq, int64(1)),
+			Right: entity.FilterAnd{Left: high, Right: backend},
+		}},
+		{"(id=1 or priority:high) tag:backend", entity.FilterAnd{
+			Left:  entity.FilterOr{Left: cond(entity.FilterID, entity.OpEq, int64(1)), Right: high},
+			Right: backend,
+		}},
+		{"NOT priority:high AND tag:backend", entity.FilterAnd{Left: entity.FilterNot{Expr: high}, Right: backend}},
+		{"not (priority:high or tag:backend)", entity.FilterNot{Expr: entity.FilterOr{Left: high, Right: backend}}},
+		{"NOT NOT tag:backend", entity.FilterNot{Expr: entity.FilterNot{Expr: backend}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			got, err := parseFilter(tt.src, testWorkflow())
+			if err != nil {
+				t.Fatalf("parseFilter: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got  %#v\nwant %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Без workflow (scope=shared/all) статус чужого владельца не отклоняется,
+// проверяется только формат ключа.
+func TestParseFilterWithoutWorkflow(t *testing.T) {
+	got, err := parseFilter("status:qa_passed", nil)
+	if err != nil {
+		t.Fatalf("parseFilter: %v", err)
+	}
+	if want := cond(entity.FilterStatus, entity.OpIn, []entity.TaskStatus{"qa_passed"}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+	if _, err := parseFilter("status:open", nil); err != nil {
+		t.Fatalf("status:open: %v", err)
+	}
+
+	_, err = parseFilter("status:QA-passed", nil)
+	var fe *FilterError
+	if !errors.As(err, &fe) || fe.Pos != 8 {
+		t.Fatalf("malformed status: got %v", err)
+	}
+	if _, err := parseFilter("status:qa_passed", testWorkflow()); !errors.As(err, &fe) {
+		t.Fatalf("status outside own workflow: got %v, want *FilterError", err)
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []struct {
+		src   string
+		pos   int
+		token string
+	}{
+		{"foo:bar", 1, "foo"},
+		{"status:open AND", 16, ""},
+		{"status:unknown", 8, "unknown"},
+		{"status<done", 7, "<"},
+		{"priority:critical", 10, "critical"},
+		{"id=abc", 4, "abc"},
+		{"title>x", 6, ">"},
+		{"tag>=x", 4, ">="},
+		{"due_at:tomorrow", 8, "tomorrow"},
+		{"due_at:", 8, ""},
+		{"(id=1", 6, ""},
+		{"id=1)", 5, ")"},
+		{`title:"abc`, 7, `"abc`},
+		{"id 1", 4, "1"},
+		{"OR id=1", 1, "OR"},
+		{"id=1 отчёт", 6, "отчёт"}, // позиция в символах, а не в байтах
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := parseFilter(tt.src, testWorkflow())
+			var fe *FilterError
+			if !errors.As(err, &fe) {
+				t.Fatalf("got %v, want *FilterError", err)
+			}
+			if !errors.Is(err, ErrInvalidFilter) {
+				t.Errorf("%v does not wrap ErrInvalidFilter", err)
+			}
+			if fe.Pos != tt.pos || fe.Token != tt.token {
+				t.Errorf("position %d token %q, want %d %q (%v)", fe.Pos, fe.Token, tt.pos, tt.token, err)
+			}
+		})
+	}
+}
+
+func TestParseFilterLimits(t *testing.T) {
+	long := "title:" + strings.Repeat("я", maxFilterLength)
+	if _, err := parseFilter(long, testWorkflow()); !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("expression of %d runes: got %v", len([]rune(long)), err)
+	}
+
+	// ровно в пределах длины в рунах, хотя байт больше лимита
+	ok := "title:" + strings.Repeat("я", maxFilterLength-len("title:"))
+	if _, err := parseFilter(ok, testWorkflow()); err != nil {
+		t.Errorf("expression of %d runes: %v", maxFilterLength, err)
+	}
+
+	nested := func(n int) string {
+		return strings.Repeat("(", n) + "id=1" + strings.Repeat(")", n)
+	}
+	if _, err := parseFilter(nested(maxFilterDepth-1), testWorkflow()); err != nil {
+		t.Errorf("depth %d: %v", maxFilterDepth-1, err)
+	}
+	if _, err := parseFilter(nested(maxFilterDepth), testWorkflow()); !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("depth %d: got %v, want ErrInvalidFilter", maxFilterDepth, err)
+	}
+	if _, err := parseFilter(strings.Repeat("NOT ", maxFilterDepth)+"id=1", testWorkflow()); !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("%d NOTs: got %v, want ErrInvalidFilter", maxFilterDepth, err)
+	}
+}
@@ -3,18 +3,186 @@ package usecase
 import (
 	"app/internal/entity"
 	"context"
+	"io"
+	"time"
 )
 
 type RepoUser interface {
 	Register(ctx context.Context, user *entity.User) (int64, error)
 	GetByID(ctx context.Context, id int64) (*entity.User, error)
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
+
+	List(ctx context.Context, search string, limit, offset int) ([]*entity.User, int64, error)
+	SetDisabled(ctx context.Context, id int64, disabled bool) error
+	Delete(ctx context.Context, id int64) error
+	TaskCounts(ctx context.Context, id int64, closed []entity.TaskStatus) (*entity.UserTaskCounts, error)
+	RequirePasswordReset(ctx context.Context, id int64, tokenHash string, expiresAt time.Time) error
+	CreatePasswordReset(ctx context.Context, userID int64, tokenHash string, expiresAt, since time.Time) (bool, error)
+	CreateEmailVerification(ctx context.Context, userID int64, tokenHash string, expiresAt, since time.Time) (bool, error)
+	VerifyEmail(ctx context.Context, tokenHash string) (int64, error)
+	ResetPassword(ctx context.Context, tokenHash string, passwordHash []byte, tokensValidAfter time.Time) (int64, error)
+	RevokeTokens(ctx context.Context, id int64, validAfter time.Time) error
+}
+
+// RepoSession хранит сессии пользователей и хэши их refresh-токенов.
+// Rotate для неизвестного, истёкшего или отозванного токена возвращает
+// sql.ErrNoRows, а для уже использованного отзывает сессию и возвращает false.
+type RepoSession interface {
+	Create(ctx context.Context, session *entity.Session, tokenHash string, expiresAt time.Time) (*entity.Session, error)
+	Rotate(ctx context.Context, tokenHash, newHash string, expiresAt time.Time) (*entity.Session, bool, error)
+	ListActive(ctx context.Context, userID int64) ([]*entity.Session, error)
+	Revoke(ctx context.Context, id, userID int64) error
+	RevokedSince(ctx context.Context, since time.Time) ([]*entity.Session, error)
+	SetLastSeen(ctx context.Context, seen map[int64]time.Time) error
+	DeleteExpired(ctx context.Context, revokedBefore time.Time) error
+}
+
+// RepoRevocation хранит JWT, отозванные до истечения срока.
+type RepoRevocation interface {
+	Revoke(ctx context.Context, token entity.RevokedToken) error
+	RevokedSince(ctx context.Context, since time.Time) ([]entity.RevokedToken, error)
+	DeleteExpired(ctx context.Context) error
 }
 
 type RepoTask interface {
 	Create(ctx context.Context, task *entity.Task) (*entity.Task, error)
 	Update(ctx context.Context, task *entity.Task) (*entity.Task, error)
+	UpdateStatus(ctx context.Context, task *entity.Task, effects *entity.StatusEffects) (bool, error)
 	Delete(ctx context.Context, id int64, ownerID int64) error
 	GetByID(ctx context.Context, id int64, ownerID int64) (*entity.Task, error)
-	List(ctx context.Context, ownerID int64) ([]*entity.Task, error)
+	List(ctx context.Context, userID int64, query entity.TaskQuery) ([]*entity.Task, error)
+	Count(ctx context.Context, userID int64, query entity.TaskQuery) (int64, error)
+	Search(ctx context.Context, ownerID int64, tsquery string, limit, offset int) ([]*entity.TaskSearchHit, error)
+
+	SetParent(ctx context.Context, id, ownerID int64, parentID *int64) (bool, error)
+	Descendants(ctx context.Context, id, ownerID int64) ([]*entity.Task, error)
+	ChildStatusCounts(ctx context.Context, parentIDs []int64) (map[int64]map[entity.TaskStatus]int, error)
+	HasDescendantsIn(ctx context.Context, id, ownerID int64, statuses []entity.TaskStatus) (bool, error)
+	DeleteTree(ctx context.Context, id, ownerID int64) error
+
+	AddDependency(ctx context.Context, taskID, dependsOnID, ownerID int64) (bool, error)
+	RemoveDependency(ctx context.Context, taskID, dependsOnID, ownerID int64) error
+	BlockersIn(ctx context.Context, id, ownerID int64, statuses []entity.TaskStatus) ([]int64, error)
+	DependencyGraph(ctx context.Context, id, ownerID int64) (*entity.DependencyGraph, error)
+
+	SetAssignee(ctx context.Context, id, ownerID int64, assigneeID *int64) error
+}
+
+type RepoWorkflow interface {
+	ListStates(ctx context.Context, ownerID int64) ([]entity.WorkflowState, error)
+	ListTransitions(ctx context.Context, ownerID int64) ([]entity.WorkflowTransition, error)
+	CreateState(ctx context.Context, ownerID int64, state entity.WorkflowState) error
+	DeleteState(ctx context.Context, ownerID int64, key entity.TaskStatus) error
+	StateInUse(ctx context.Context, ownerID int64, key entity.TaskStatus) (bool, error)
+	AddTransition(ctx context.Context, ownerID int64, from, to entity.TaskStatus) error
+	DeleteTransition(ctx context.Context, ownerID int64, from, to entity.TaskStatus) error
+}
+
+type RepoLabel interface {
+	Create(ctx context.Context, label *entity.Label) (*entity.Label, error)
+	Update(ctx context.Context, label *entity.Label) (*entity.Label, error)
+	Delete(ctx context.Context, id int64) error
+	GetByID(ctx context.Context, id int64) (*entity.Label, error)
+	GetByName(ctx context.Context, name string) (*entity.Label, error)
+	List(ctx context.Context) ([]*entity.Label, error)
+	Attach(ctx context.Context, taskID int64, labelIDs []int64) error
+	Detach(ctx context.Context, taskID, labelID int64) error
+}
+
+type RepoProject interface {
+	Create(ctx context.Context, project *entity.Project) (*entity.Project, error)
+	Update(ctx context.Context, project *entity.Project) (*entity.Project, error)
+	Delete(ctx context.Context, id, ownerID int64) error
+	GetByID(ctx context.Context, id, ownerID int64) (*entity.Project, error)
+	List(ctx context.Context, ownerID int64) ([]*entity.Project, error)
+	ListShared(ctx context.Context, userID int64) ([]*entity.Project, error)
+}
+
+// RepoShare хранит выданные доступы. TaskRole и ProjectRole возвращают роль
+// пользователя и владельца ресурса либо sql.ErrNoRows, если доступа нет.
+type RepoShare interface {
+	TaskRole(ctx context.Context, taskID, userID int64) (entity.Role, int64, error)
+	ProjectRole(ctx context.Context, projectID, userID int64) (entity.Role, int64, error)
+	Grant(ctx context.Context, target entity.ShareTarget, id, userID int64, role entity.Role) error
+	Revoke(ctx context.Context, target entity.ShareTarget, id, userID int64) error
+	List(ctx context.Context, target entity.ShareTarget, id int64) ([]*entity.Share, error)
+}
+
+// RepoWorkspace хранит рабочие пространства и их участников. SetRole и
+// RemoveMember возвращают false, если в пространстве не осталось бы
+// администратора.
+type RepoWorkspace interface {
+	Create(ctx context.Context, name string, adminID int64) (*entity.Workspace, error)
+	ListForUser(ctx context.Context, userID int64) ([]*entity.Workspace, error)
+	Membership(ctx context.Context, workspaceID, userID int64) (*entity.Membership, error)
+	Personal(ctx context.Context, userID int64) (*entity.Membership, error)
+	Members(ctx context.Context, workspaceID int64) ([]*entity.Membership, error)
+	SetRole(ctx context.Context, workspaceID, userID int64, role entity.WorkspaceRole) (bool, error)
+	RemoveMember(ctx context.Context, workspaceID, userID int64) (bool, error)
+	CreateInvitation(ctx context.Context, inv *entity.Invitation, tokenHash string, invitedBy int64) (*entity.Invitation, error)
+	AcceptInvitation(ctx context.Context, tokenHash string, userID int64, email string) (*entity.Membership, error)
+}
+
+// RepoRBAC хранит системные роли и их разрешения. Grant для неизвестной
+// роли или пользователя возвращает sql.ErrNoRows, Revoke возвращает false
+// для последнего администратора.
+type RepoRBAC interface {
+	Roles(ctx context.Context) ([]*entity.AccountRole, error)
+	UserRoles(ctx context.Context, userID int64) ([]*entity.RoleGrant, error)
+	Grant(ctx context.Context, userID int64, role string, grantedBy *int64) error
+	Revoke(ctx context.Context, userID int64, role string) (bool, error)
+}
+
+type RepoReminder interface {
+	Create(ctx context.Context, reminder *entity.Reminder) (*entity.Reminder, error)
+	ListByTask(ctx context.Context, taskID, ownerID int64) ([]*entity.Reminder, error)
+	Delete(ctx context.Context, id, taskID, ownerID int64) error
+	Claim(ctx context.Context, limit int, lease time.Duration, maxAttempts int) ([]entity.ReminderDelivery, error)
+	MarkSent(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, reason string, retryAt time.Time) error
+}
+
+// Notifier доставляет сработавшие напоминания пользователю.
+type Notifier interface {
+	NotifyReminder(ctx context.Context, task *entity.Task, reminder *entity.Reminder) error
+}
+
+// Mailer отправляет письма пользователям.
+type Mailer interface {
+	Send(ctx context.Context, msg entity.Email) error
+}
+
+type RepoComment interface {
+	Create(ctx context.Context, comment *entity.Comment) (*entity.Comment, error)
+	Update(ctx context.Context, comment *entity.Comment) (*entity.Comment, error)
+	Delete(ctx context.Context, id, taskID int64) error
+	GetByID(ctx context.Context, id, taskID int64) (*entity.Comment, error)
+	ListByTask(ctx context.Context, taskID int64) ([]*entity.Comment, error)
+}
+
+type RepoAttachment interface {
+	Create(ctx context.Context, attachment *entity.Attachment) (*entity.Attachment, error)
+	GetByID(ctx context.Context, id, taskID int64) (*entity.Attachment, error)
+	ListByTask(ctx context.Context, taskID int64) ([]*entity.Attachment, error)
+	Delete(ctx context.Context, id, taskID int64) error
+	PendingBlobDeletions(ctx context.Context, after string, limit int) ([]string, error)
+	ForgetBlobDeletion(ctx context.Context, key string) error
+}
+
+// BlobStore хранит содержимое вложений. Для отсутствующего объекта Open
+// возвращает ошибку, для которой errors.Is(err, fs.ErrNotExist); Delete
+// отсутствующего объекта ошибкой не считается.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type RepoChecklist interface {
+	List(ctx context.Context, taskID int64) ([]*entity.ChecklistItem, error)
+	GetByID(ctx context.Context, id, taskID int64) (*entity.ChecklistItem, error)
+	Add(ctx context.Context, taskID int64, text string, position *int) (*entity.ChecklistItem, error)
+	Update(ctx context.Context, item *entity.ChecklistItem) (*entity.ChecklistItem, error)
+	Delete(ctx context.Context, id, taskID int64) error
+	Reorder(ctx context.Context, taskID int64, ids []int64) (bool, error)
 }
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const defaultLabelColor = "#808080"
+
+var labelColorRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type LabelUseCase struct {
+	repo   RepoLabel
+	tasks  RepoTask
+	access *Authorizer
+}
+
+func NewLabelUseCase(repo RepoLabel, tasks RepoTask, access *Authorizer) *LabelUseCase {
+	return &LabelUseCase{repo: repo, tasks: tasks, access: access}
+}
+
+func normalizeLabel(label *entity.Label) error {
+	label.Name = strings.TrimSpace(label.Name)
+	if label.Name == "" || len([]rune(label.Name)) > 64 {
+		return fmt.Errorf("%w: имя метки должно быть от 1 до 64 символов", ErrInvalidInput)
+	}
+	if strings.ContainsAny(label.Name, " \t\"(),") {
+		// иначе метку не получится указать в фильтре и в ?labels=
+		return fmt.Errorf("%w: имя метки не может содержать пробелы, кавычки, скобки и запятые", ErrInvalidInput)
+	}
+	if label.Color == "" {
+		label.Color = defaultLabelColor
+	}
+	if !labelColorRe.MatchString(label.Color) {
+		return fmt.Errorf("%w: цвет должен быть в формате #RRGGBB", ErrInvalidInput)
+	}
+	label.Color = strings.ToLower(label.Color)
+	return nil
+}
+
+// checkNameFree проверяет, что в пространстве нет другой метки с таким именем.
+func (l *LabelUseCase) checkNameFree(ctx context.Context, label *entity.Label) error {
+	existing, err := l.repo.GetByName(ctx, label.Name)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if existing != nil && existing.ID != label.ID {
+		return ErrLabelExists
+	}
+	return nil
+}
+
+// CreateLabel заводит метку в текущем пространстве. Метками пространства
+// управляют его участники; гость только видит их на выданных задачах.
+func (l *LabelUseCase) CreateLabel(ctx context.Context, label *entity.Label) (*entity.Label, error) {
+	if err := denyGuest(ctx); err != nil {
+		return nil, err
+	}
+	if err := normalizeLabel(label); err != nil {
+		return nil, err
+	}
+	if err := l.checkNameFree(ctx, label); err != nil {
+		return nil, err
+	}
+	return l.repo.Create(ctx, label)
+}
+
+func (l *LabelUseCase) UpdateLabel(ctx context.Context, label *entity.Label) (*entity.Label, error) {
+	if err := denyGuest(ctx); err != nil {
+		return nil, err
+	}
+	if err := normalizeLabel(label); err != nil {
+		return nil, err
+	}
+	if err := l.checkNameFree(ctx, label); err != nil {
+		return nil, err
+	}
+	return l.repo.Update(ctx, label)
+}
+
+func (l *LabelUseCase) DeleteLabel(ctx context.Context, id int64) error {
+	if err := denyGuest(ctx); err != nil {
+		return err
+	}
+	return l.repo.Delete(ctx, id)
+}
+
+func (l *LabelUseCase) GetLabel(ctx context.Context, id int64) (*entity.Label, error) {
+	return l.repo.GetByID(ctx, id)
+}
+
+func (l *LabelUseCase) ListLabels(ctx context.Context) ([]*entity.Label, error) {
+	return l.repo.List(ctx)
+}
+
+// AttachLabels вешает метки на задачу и возвращает её с обновлённым списком
+// меток. Метки берутся из набора пространства, в котором лежит задача.
+func (l *LabelUseCase) AttachLabels(ctx context.Context, taskID, userID int64, labelIDs []int64) (*entity.Task, error) {
+	if len(labelIDs) == 0 {
+		return nil, fmt.Errorf("%w: не указаны метки", ErrInvalidInput)
+	}
+	ownerID, err := l.access.TaskOwner(ctx, taskID, userID, entity.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	unique := make([]int64, 0, len(labelIDs))
+	seen := make(map[int64]bool, len(labelIDs))
+	for _, id := range labelIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if err := l.repo.Attach(ctx, taskID, unique); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrLabelNotFound
+		}
+		return nil, err
+	}
+	return l.tasks.GetByID(ctx, taskID, ownerID)
+}
+
+func (l *LabelUseCase) DetachLabel(ctx context.Context, taskID, userID, labelID int64) (*entity.Task, error) {
+	ownerID, err := l.access.TaskOwner(ctx, taskID, userID, entity.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.repo.Detach(ctx, taskID, labelID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrLabelNotFound
+		}
+		return nil, err
+	}
+	return l.tasks.GetByID(ctx, taskID, ownerID)
+}
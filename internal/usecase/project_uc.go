@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"context"
+	"fmt"
+	"strings"
+)
+
+type ProjectUseCase struct {
+	repo   RepoProject
+	access *Authorizer
+}
+
+func NewProjectUseCase(repo RepoProject, access *Authorizer) *ProjectUseCase {
+	return &ProjectUseCase{repo: repo, access: access}
+}
+
+func validateProject(project *entity.Project) error {
+	project.Name = strings.TrimSpace(project.Name)
+	if project.Name == "" {
+		return fmt.Errorf("%w: у проекта должно быть имя", ErrInvalidInput)
+	}
+	return nil
+}
+
+func (p *ProjectUseCase) CreateProject(ctx context.Context, project *entity.Project) (*entity.Project, error) {
+	if err := validateProject(project); err != nil {
+		return nil, err
+	}
+	if err := denyGuest(ctx); err != nil {
+		return nil, err
+	}
+	return p.repo.Create(ctx, project)
+}
+
+func (p *ProjectUseCase) UpdateProject(ctx context.Context, userID int64, project *entity.Project) (*entity.Project, error) {
+	if err := validateProject(project); err != nil {
+		return nil, err
+	}
+	current, err := p.access.Project(ctx, project.ID, userID, entity.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	project.OwnerID = current.OwnerID
+	return p.repo.Update(ctx, project)
+}
+
+// DeleteProject удаляет проект; его задачи остаются у владельца без проекта.
+func (p *ProjectUseCase) DeleteProject(ctx context.Context, id, userID int64) error {
+	project, err := p.access.Project(ctx, id, userID, entity.RoleOwner)
+	if err != nil {
+		return err
+	}
+	return p.repo.Delete(ctx, id, project.OwnerID)
+}
+
+func (p *ProjectUseCase) GetProject(ctx context.Context, id, userID int64) (*entity.Project, error) {
+	return p.access.Project(ctx, id, userID, entity.RoleViewer)
+}
+
+// ListProjects отдаёт свои проекты, чужие, к которым выдан доступ
+// (ScopeShared), или и те и другие (ScopeAll).
+func (p *ProjectUseCase) ListProjects(ctx context.Context, userID int64, scope entity.Scope) ([]*entity.Project, error) {
+	switch scope {
+	case entity.ScopeShared:
+		return p.repo.ListShared(ctx, userID)
+	case entity.ScopeAll:
+		own, err := p.repo.List(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		shared, err := p.repo.ListShared(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		return append(own, shared...), nil
+	}
+	return p.repo.List(ctx, userID)
+}
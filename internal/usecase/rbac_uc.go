@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// permissionsTTL — как долго держится в памяти таблица «роль → разрешения»:
+// изменения разрешений ролей в базе подхватываются не позже чем через минуту.
+const permissionsTTL = time.Minute
+
+// RBACUseCase выдаёт системные роли и проверяет разрешения. Роли
+// пользователя читаются из базы на каждый запрос (RoleNames), поэтому
+// выданная или отозванная роль действует сразу; роли в JWT — справочные.
+type RBACUseCase struct {
+	repo  RepoRBAC
+	users RepoUser
+
+	mu       sync.RWMutex
+	perms    map[string]map[entity.Permission]bool
+	loadedAt time.Time
+}
+
+func NewRBACUseCase(repo RepoRBAC, users RepoUser) *RBACUseCase {
+	return &RBACUseCase{repo: repo, users: users}
+}
+
+// Allowed сообщает, даёт ли хотя бы одна из ролей разрешение perm.
+func (u *RBACUseCase) Allowed(ctx context.Context, roles []string, perm entity.Permission) (bool, error) {
+	perms, err := u.permissions(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, role := range roles {
+		if perms[role][perm] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (u *RBACUseCase) permissions(ctx context.Context) (map[string]map[entity.Permission]bool, error) {
+	u.mu.RLock()
+	perms, fresh := u.perms, time.Since(u.loadedAt) < permissionsTTL
+	u.mu.RUnlock()
+	if perms != nil && fresh {
+		return perms, nil
+	}
+
+	roles, err := u.repo.Roles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	perms = make(map[string]map[entity.Permission]bool, len(roles))
+	for _, role := range roles {
+		set := make(map[entity.Permission]bool, len(role.Permissions))
+		for _, p := range role.Permissions {
+			set[p] = true
+		}
+		perms[role.Name] = set
+	}
+
+	u.mu.Lock()
+	u.perms, u.loadedAt = perms, time.Now()
+	u.mu.Unlock()
+	return perms, nil
+}
+
+// RoleNames возвращает имена системных ролей пользователя.
+func (u *RBACUseCase) RoleNames(ctx context.Context, userID int64) ([]string, error) {
+	grants, err := u.repo.UserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(grants))
+	for i, g := range grants {
+		names[i] = g.Role
+	}
+	return names, nil
+}
+
+func (u *RBACUseCase) ListRoles(ctx context.Context) ([]*entity.AccountRole, error) {
+	return u.repo.Roles(ctx)
+}
+
+// UserRoles возвращает роли пользователя; для несуществующего — ErrUserNotFound.
+func (u *RBACUseCase) UserRoles(ctx context.Context, userID int64) ([]*entity.RoleGrant, error) {
+	if _, err := u.users.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return u.repo.UserRoles(ctx, userID)
+}
+
+// GrantRole выдаёт пользователю userID роль от имени администратора adminID.
+func (u *RBACUseCase) GrantRole(ctx context.Context, adminID, userID int64, role string) ([]*entity.RoleGrant, error) {
+	role = strings.TrimSpace(role)
+	if role == "" {
+		return nil, fmt.Errorf("%w: нужна роль", ErrInvalidInput)
+	}
+	if _, err := u.UserRoles(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := u.repo.Grant(ctx, userID, role, &adminID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrRoleNotFound, role)
+		}
+		return nil, err
+	}
+	return u.repo.UserRoles(ctx, userID)
+}
+
+// RevokeRole отзывает роль; последнего администратора разжаловать нельзя.
+func (u *RBACUseCase) RevokeRole(ctx context.Context, userID int64, role string) ([]*entity.RoleGrant, error) {
+	ok, err := u.repo.Revoke(ctx, userID, role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: у пользователя нет роли %s", ErrRoleNotFound, role)
+		}
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrLastSystemAdmin
+	}
+	return u.repo.UserRoles(ctx, userID)
+}
+
+// Bootstrap выдаёт роль admin пользователю с почтой email, если он уже
+// зарегистрирован, — так в системе появляется первый администратор.
+func (u *RBACUseCase) Bootstrap(ctx context.Context, email string) error {
+	email = normalizeEmail(email)
+	if email == "" {
+		return nil
+	}
+	user, err := u.users.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	return u.repo.Grant(ctx, user.ID, entity.RoleNameAdmin, nil)
+}
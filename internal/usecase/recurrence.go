@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultOccurrences = 5
+	maxOccurrences     = 100
+)
+
+// setRecurrence проверяет правило и приводит его к каноническому виду.
+// Серия начинается с текущего срока задачи, поэтому без due_at повторение
+// задать нельзя. Пустое правило снимает повторение.
+func setRecurrence(task *entity.Task, rule string) error {
+	if rule == "" {
+		task.Recurrence = ""
+		task.RecurrenceStart = nil
+		return nil
+	}
+	r, err := entity.ParseRecurrence(rule)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+	if task.DueAt == nil {
+		return fmt.Errorf("%w: для повторения нужен due_at", ErrInvalidRecurrence)
+	}
+	task.Recurrence = r.String()
+	task.RecurrenceStart = task.DueAt
+	return nil
+}
+
+// SetRecurrence задаёт задаче правило повторения; пустое правило его снимает.
+func (t *TaskUseCase) SetRecurrence(ctx context.Context, taskID, userID int64, rule string) (*entity.Task, error) {
+	task, err := t.access.Task(ctx, taskID, userID, entity.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if err := setRecurrence(task, rule); err != nil {
+		return nil, err
+	}
+	return t.repo.Update(ctx, task)
+}
+
+// PreviewOccurrences возвращает сроки следующих n повторений после текущего.
+func (t *TaskUseCase) PreviewOccurrences(ctx context.Context, taskID, userID int64, n int) ([]time.Time, error) {
+	if n <= 0 {
+		n = defaultOccurrences
+	}
+	if n > maxOccurrences {
+		n = maxOccurrences
+	}
+	task, err := t.access.Task(ctx, taskID, userID, entity.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	r, start, ok := taskRecurrence(task)
+	if !ok {
+		return nil, fmt.Errorf("%w: задача не повторяется", ErrInvalidRecurrence)
+	}
+	return r.Occurrences(start, *task.DueAt, n), nil
+}
+
+// nextOccurrence собирает следующее повторение задачи, которую выполняют;
+// сохраняет его репозиторий вместе с задачей. Если серия закончилась
+// (COUNT/UNTIL), возвращает nil.
+func nextOccurrence(task *entity.Task) *entity.Task {
+	r, start, ok := taskRecurrence(task)
+	if !ok {
+		return nil
+	}
+	due := *task.DueAt
+	occurrences := r.Occurrences(start, due, 1)
+	if len(occurrences) == 0 {
+		return nil
+	}
+	nextDue := occurrences[0]
+
+	next := &entity.Task{
+		OwnerID:         task.OwnerID,
+		AssigneeID:      task.AssigneeID,
+		ProjectID:       task.ProjectID,
+		ParentID:        task.ParentID,
+		Title:           task.Title,
+		Description:     task.Description,
+		Status:          entity.StatusTodo,
+		Priority:        task.Priority,
+		DueAt:           &nextDue,
+		Recurrence:      task.Recurrence,
+		RecurrenceStart: task.RecurrenceStart,
+	}
+	if task.StartAt != nil {
+		// начало сдвигается вместе со сроком
+		startAt := task.StartAt.Add(nextDue.Sub(due))
+		next.StartAt = &startAt
+	}
+	return next
+}
+
+// taskRecurrence разбирает сохранённое правило задачи. Задачи без срока или
+// с испорченным правилом считаются неповторяющимися.
+func taskRecurrence(task *entity.Task) (entity.Recurrence, time.Time, bool) {
+	if task.Recurrence == "" || task.DueAt == nil {
+		return entity.Recurrence{}, time.Time{}, false
+	}
+	r, err := entity.ParseRecurrence(task.Recurrence)
+	if err != nil {
+		return entity.Recurrence{}, time.Time{}, false
+	}
+	start := *task.DueAt
+	if task.RecurrenceStart != nil {
+		start = *task.RecurrenceStart
+	}
+	return r, start, true
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	reminderBatch       = 50
+	reminderLease       = 5 * time.Minute
+	reminderMaxAttempts = 10
+	maxReminderOffset   = 365 * 24 * 60 // минут
+)
+
+type ReminderUseCase struct {
+	repo     RepoReminder
+	access   *Authorizer
+	workflow RepoWorkflow
+	notifier Notifier
+}
+
+func NewReminderUseCase(repo RepoReminder, access *Authorizer, workflow RepoWorkflow, notifier Notifier) *ReminderUseCase {
+	return &ReminderUseCase{repo: repo, access: access, workflow: workflow, notifier: notifier}
+}
+
+// CreateReminder добавляет напоминание: за offsetMinutes до срока задачи или в remindAt.
+func (u *ReminderUseCase) CreateReminder(ctx context.Context, taskID, userID int64, offsetMinutes *int, remindAt *time.Time) (*entity.Reminder, error) {
+	if (offsetMinutes == nil) == (remindAt == nil) {
+		return nil, fmt.Errorf("%w: нужно указать либо offset_minutes, либо remind_at", ErrInvalidInput)
+	}
+	task, err := u.access.Task(ctx, taskID, userID, entity.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if offsetMinutes != nil {
+		if *offsetMinutes < 0 || *offsetMinutes > maxReminderOffset {
+			return nil, fmt.Errorf("%w: offset_minutes должен быть от 0 до %d", ErrInvalidInput, maxReminderOffset)
+		}
+		if task.DueAt == nil {
+			return nil, fmt.Errorf("%w: для напоминания до срока нужен due_at", ErrInvalidInput)
+		}
+	}
+	return u.repo.Create(ctx, &entity.Reminder{TaskID: taskID, OffsetMinutes: offsetMinutes, RemindAt: remindAt})
+}
+
+func (u *ReminderUseCase) ListReminders(ctx context.Context, taskID, userID int64) ([]*entity.Reminder, error) {
+	ownerID, err := u.access.TaskOwner(ctx, taskID, userID, entity.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return u.repo.ListByTask(ctx, taskID, ownerID)
+}
+
+func (u *ReminderUseCase) DeleteReminder(ctx context.Context, id, taskID, userID int64) error {
+	ownerID, err := u.access.TaskOwner(ctx, taskID, userID, entity.RoleEditor)
+	if err != nil {
+		return err
+	}
+	if err := u.repo.Delete(ctx, id, taskID, ownerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrReminderNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// FireDue доставляет все сработавшие напоминания. Вызывается планировщиком;
+// безопасен при запуске сразу на нескольких репликах.
+func (u *ReminderUseCase) FireDue(ctx context.Context) error {
+	workflows := map[int64]*entity.Workflow{}
+	for {
+		batch, err := u.repo.Claim(ctx, reminderBatch, reminderLease, reminderMaxAttempts)
+		if err != nil {
+			return err
+		}
+		for _, d := range batch {
+			if err := u.deliver(ctx, d, workflows); err != nil {
+				return err
+			}
+		}
+		if len(batch) < reminderBatch {
+			return nil
+		}
+	}
+}
+
+func (u *ReminderUseCase) deliver(ctx context.Context, d entity.ReminderDelivery, workflows map[int64]*entity.Workflow) error {
+	wf, ok := workflows[d.Task.OwnerID]
+	if !ok {
+		var err error
+		if wf, err = loadWorkflow(ctx, u.workflow, d.Task.OwnerID); err != nil {
+			return err
+		}
+		workflows[d.Task.OwnerID] = wf
+	}
+	if wf.IsClosed(d.Task.Status) {
+		// о закрытой задаче не напоминаем
+		return u.repo.MarkSent(ctx, d.Reminder.ID)
+	}
+
+	if err := u.notifier.NotifyReminder(ctx, d.Task, d.Reminder); err != nil {
+		attempts := d.Reminder.Attempts
+		log.Printf("reminder %d: delivery attempt %d failed: %v", d.Reminder.ID, attempts, err)
+		return u.repo.MarkFailed(ctx, d.Reminder.ID, err.Error(), time.Now().Add(reminderBackoff(attempts)))
+	}
+	return u.repo.MarkSent(ctx, d.Reminder.ID)
+}
+
+// reminderBackoff — пауза перед следующей попыткой: 1, 2, 4… минуты, не больше часа.
+func reminderBackoff(attempts int) time.Duration {
+	d := time.Minute
+	for i := 1; i < attempts && d < time.Hour; i++ {
+		d *= 2
+	}
+	return min(d, time.Hour)
+}
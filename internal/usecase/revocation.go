@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"context"
+	"sync"
+	"time"
+)
+
+// revocationSyncOverlap — насколько раньше прошлой синхронизации
+// перечитывать отзывы: запись, сделанная в долгой транзакции или другим
+// экземпляром с отстающими часами, может получить время отзыва из прошлого.
+const revocationSyncOverlap = time.Minute
+
+// revocationCache — отозванные JWT и сессии в памяти, чтобы AuthMiddleware
+// не ходил за ними в базу на каждый запрос. Отзывы этого экземпляра видны
+// сразу, чужие — после очередного sync.
+type revocationCache struct {
+	tokenRepo   RepoRevocation
+	sessionRepo RepoSession
+	// accessTTL — сколько после отзыва сессии могут жить выданные в ней JWT.
+	accessTTL time.Duration
+
+	mu       sync.RWMutex
+	tokens   map[string]time.Time // jti → когда токен истечёт сам
+	sessions map[int64]time.Time  // id сессии → когда истекут её JWT
+	syncedAt time.Time
+}
+
+func newRevocationCache(tokens RepoRevocation, sessions RepoSession, accessTTL time.Duration) *revocationCache {
+	return &revocationCache{
+		tokenRepo:   tokens,
+		sessionRepo: sessions,
+		accessTTL:   accessTTL,
+		tokens:      map[string]time.Time{},
+		sessions:    map[int64]time.Time{},
+	}
+}
+
+func (c *revocationCache) revokeToken(ctx context.Context, token entity.RevokedToken) error {
+	if err := c.tokenRepo.Revoke(ctx, token); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.tokens[token.ID] = token.ExpiresAt
+	c.mu.Unlock()
+	return nil
+}
+
+// sessionRevoked запоминает сессию, уже отозванную в базе.
+func (c *revocationCache) sessionRevoked(id int64) {
+	c.mu.Lock()
+	c.sessions[id] = time.Now().Add(c.accessTTL)
+	c.mu.Unlock()
+}
+
+// isRevoked — токен отозван сам по себе или вместе со своей сессией.
+func (c *revocationCache) isRevoked(jti string, sessionID int64) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if _, ok := c.tokens[jti]; ok && jti != "" {
+		return true
+	}
+	_, ok := c.sessions[sessionID]
+	return ok && sessionID != 0
+}
+
+// sync дочитывает отзывы, сделанные с прошлой синхронизации, и забывает
+// те, чьи токены уже истекли. Первый вызов загружает все действующие отзывы.
+func (c *revocationCache) sync(ctx context.Context) error {
+	now := time.Now()
+	c.mu.RLock()
+	since := c.syncedAt
+	c.mu.RUnlock()
+	if !since.IsZero() {
+		since = since.Add(-revocationSyncOverlap)
+	}
+
+	tokens, err := c.tokenRepo.RevokedSince(ctx, since)
+	if err != nil {
+		return err
+	}
+	// JWT сессий, отозванных раньше чем accessTTL назад, уже истекли
+	if oldest := now.Add(-c.accessTTL); since.Before(oldest) {
+		since = oldest
+	}
+	sessions, err := c.sessionRepo.RevokedSince(ctx, since)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, t := range tokens {
+		c.tokens[t.ID] = t.ExpiresAt
+	}
+	for _, s := range sessions {
+		c.sessions[s.ID] = s.RevokedAt.Add(c.accessTTL)
+	}
+	for jti, expiresAt := range c.tokens {
+		if expiresAt.Before(now) {
+			delete(c.tokens, jti)
+		}
+	}
+	for id, expiresAt := range c.sessions {
+		if expiresAt.Before(now) {
+			delete(c.sessions, id)
+		}
+	}
+	c.syncedAt = now
+	return nil
+}
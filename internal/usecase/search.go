@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	maxSearchTerms     = 16
+)
+
+// searchQuery превращает пользовательский запрос в tsquery: каждое слово
+// ищется по префиксу («отч» найдёт «отчёт»), слова объединяются через AND.
+// В запрос попадают только буквы и цифры, поэтому синтаксис tsquery из
+// пользовательского ввода не протечёт.
+func searchQuery(q string) string {
+	words := strings.FieldsFunc(q, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > maxSearchTerms {
+		words = words[:maxSearchTerms]
+	}
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		terms = append(terms, strings.ToLower(w)+":*")
+	}
+	return strings.Join(terms, " & ")
+}
+
+func (t *TaskUseCase) SearchTasks(ctx context.Context, ownerID int64, q string, limit, offset int) ([]*entity.TaskSearchHit, error) {
+	tsquery := searchQuery(q)
+	if tsquery == "" {
+		return nil, fmt.Errorf("%w: пустой поисковый запрос", ErrInvalidInput)
+	}
+	switch {
+	case limit < 0 || offset < 0:
+		return nil, fmt.Errorf("%w: limit и offset не могут быть отрицательными", ErrInvalidInput)
+	case limit == 0:
+		limit = defaultSearchLimit
+	case limit > maxSearchLimit:
+		limit = maxSearchLimit
+	}
+	return t.repo.Search(ctx, ownerID, tsquery, limit, offset)
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+)
+
+// lastSeenResolution — с какой точностью храним время активности сессии:
+// чаще отметку не обновляем, даже в памяти.
+const lastSeenResolution = time.Minute
+
+// maxUserAgentLen — сколько символов User-Agent сохраняем в сессии.
+const maxUserAgentLen = 512
+
+// lastSeenTracker копит отметки активности сессий в памяти; flush пишет
+// их в базу пачкой, чтобы запросы не делали по записи каждый.
+type lastSeenTracker struct {
+	mu      sync.Mutex
+	pending map[int64]time.Time // ещё не записанные отметки
+	seen    map[int64]time.Time // последняя отметка по сессии
+}
+
+func newLastSeenTracker() *lastSeenTracker {
+	return &lastSeenTracker{pending: map[int64]time.Time{}, seen: map[int64]time.Time{}}
+}
+
+func (t *lastSeenTracker) touch(sessionID int64, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if at.Sub(t.seen[sessionID]) < lastSeenResolution {
+		return
+	}
+	t.seen[sessionID] = at
+	t.pending[sessionID] = at
+}
+
+// get — последняя известная этому экземпляру отметка сессии.
+func (t *lastSeenTracker) get(sessionID int64) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	at, ok := t.seen[sessionID]
+	return at, ok
+}
+
+func (t *lastSeenTracker) flush(ctx context.Context, repo RepoSession) error {
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = map[int64]time.Time{}
+	// seen нужен только для троттлинга: отметки старше шага уже ничего не отсекают
+	for id, at := range t.seen {
+		if time.Since(at) >= lastSeenResolution {
+			delete(t.seen, id)
+		}
+	}
+	t.mu.Unlock()
+
+	if err := repo.SetLastSeen(ctx, pending); err != nil {
+		// вернём отметки, чтобы записать их в следующий раз
+		t.mu.Lock()
+		for id, at := range pending {
+			if at.After(t.pending[id]) {
+				t.pending[id] = at
+			}
+		}
+		t.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// ListSessions возвращает активные сессии пользователя; current — сессия
+// текущего запроса.
+func (u *UserUseCase) ListSessions(ctx context.Context, userID, current int64) ([]*entity.Session, error) {
+	sessions, err := u.sessions.ListActive(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sessions {
+		if at, ok := u.seen.get(s.ID); ok && at.After(s.LastSeenAt) {
+			s.LastSeenAt = at
+		}
+		s.Current = s.ID == current
+	}
+	return sessions, nil
+}
+
+// RevokeSession завершает сессию пользователя: её refresh-токены и уже
+// выданные JWT перестают действовать.
+func (u *UserUseCase) RevokeSession(ctx context.Context, userID, sessionID int64) error {
+	if err := u.sessions.Revoke(ctx, sessionID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	u.revoked.sessionRevoked(sessionID)
+	return nil
+}
+
+// FlushLastSeen записывает накопленные отметки активности сессий.
+// Вызывается планировщиком.
+func (u *UserUseCase) FlushLastSeen(ctx context.Context) error {
+	return u.seen.flush(ctx, u.sessions)
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"app/internal/tenant"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ShareUseCase выдаёт и отзывает доступ к задачам и проектам.
+type ShareUseCase struct {
+	repo       RepoShare
+	users      RepoUser
+	workspaces RepoWorkspace
+	access     *Authorizer
+}
+
+func NewShareUseCase(repo RepoShare, users RepoUser, workspaces RepoWorkspace, access *Authorizer) *ShareUseCase {
+	return &ShareUseCase{repo: repo, users: users, workspaces: workspaces, access: access}
+}
+
+// authorize проверяет права userID на ресурс и возвращает его владельца.
+func (u *ShareUseCase) authorize(ctx context.Context, target entity.ShareTarget, id, userID int64, min entity.Role) (int64, error) {
+	switch target {
+	case entity.ShareTask:
+		return u.access.TaskOwner(ctx, id, userID, min)
+	case entity.ShareProject:
+		project, err := u.access.Project(ctx, id, userID, min)
+		if err != nil {
+			return 0, err
+		}
+		return project.OwnerID, nil
+	}
+	return 0, fmt.Errorf("unknown share target %q", target)
+}
+
+// ListShares отдаёт всех, кому выдан доступ; видно любому участнику.
+func (u *ShareUseCase) ListShares(ctx context.Context, target entity.ShareTarget, id, userID int64) ([]*entity.Share, error) {
+	if _, err := u.authorize(ctx, target, id, userID, entity.RoleViewer); err != nil {
+		return nil, err
+	}
+	return u.repo.List(ctx, target, id)
+}
+
+// Share выдаёт доступ пользователю с указанной почтой или меняет его роль.
+// Доступ выдаётся только участникам текущего рабочего пространства.
+func (u *ShareUseCase) Share(ctx context.Context, target entity.ShareTarget, id, userID int64, email string, role entity.Role) ([]*entity.Share, error) {
+	if !role.Valid() {
+		return nil, fmt.Errorf("%w: роль должна быть viewer, editor или owner", ErrInvalidInput)
+	}
+	email = normalizeEmail(email)
+	if email == "" {
+		return nil, fmt.Errorf("%w: нужна почта пользователя", ErrInvalidInput)
+	}
+	ownerID, err := u.authorize(ctx, target, id, userID, entity.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	user, err := u.users.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	switch user.ID {
+	case ownerID:
+		return nil, fmt.Errorf("%w: у владельца и так есть полный доступ", ErrInvalidInput)
+	case userID:
+		return nil, fmt.Errorf("%w: нельзя менять собственный доступ", ErrInvalidInput)
+	}
+	workspaceID, err := tenant.WorkspaceID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.workspaces.Membership(ctx, workspaceID, user.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: пользователь не участник рабочего пространства, сначала пригласите его", ErrInvalidInput)
+		}
+		return nil, err
+	}
+	if err := u.repo.Grant(ctx, target, id, user.ID, role); err != nil {
+		return nil, err
+	}
+	return u.repo.List(ctx, target, id)
+}
+
+// Unshare отзывает доступ. Чужой доступ отзывает владелец, от своего
+// участник может отказаться сам.
+func (u *ShareUseCase) Unshare(ctx context.Context, target entity.ShareTarget, id, userID, memberID int64) error {
+	min := entity.RoleOwner
+	if memberID == userID {
+		min = entity.RoleViewer
+	}
+	if _, err := u.authorize(ctx, target, id, userID, min); err != nil {
+		return err
+	}
+	if err := u.repo.Revoke(ctx, target, id, memberID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrShareNotFound
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// parentOwner проверяет, что пользователь может добавлять подзадачи к
+// parentID, и возвращает владельца родителя; без родителя — 0.
+func (t *TaskUseCase) parentOwner(ctx context.Context, userID int64, parentID *int64) (int64, error) {
+	if parentID == nil {
+		return 0, nil
+	}
+	ownerID, err := t.access.TaskOwner(ctx, *parentID, userID, entity.RoleEditor)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrParentNotFound
+		}
+		return 0, err
+	}
+	return ownerID, nil
+}
+
+// SetParent делает задачу подзадачей parentID (nil — корневой задачей).
+// Родитель должен принадлежать владельцу задачи.
+func (t *TaskUseCase) SetParent(ctx context.Context, taskID, userID int64, parentID *int64) (*entity.Task, error) {
+	ownerID, err := t.access.TaskOwner(ctx, taskID, userID, entity.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	parentOwner, err := t.parentOwner(ctx, userID, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if parentOwner != 0 && parentOwner != ownerID {
+		return nil, fmt.Errorf("%w: родительская задача принадлежит другому владельцу", ErrInvalidInput)
+	}
+	ok, err := t.repo.SetParent(ctx, taskID, ownerID, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrSubtaskCycle
+	}
+	return t.GetTaskByID(ctx, taskID, userID)
+}
+
+// ListSubtasks отдаёт прямые подзадачи с обычными фильтрами и пагинацией.
+// Подзадачи принадлежат владельцу родителя, поэтому выбираются от его имени.
+func (t *TaskUseCase) ListSubtasks(ctx context.Context, taskID, userID int64, query entity.TaskQuery, page entity.PageRequest) (*entity.TaskPage, error) {
+	ownerID, err := t.access.TaskOwner(ctx, taskID, userID, entity.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	query.Scope = entity.ScopeOwn
+	query.ParentID = &taskID
+	return t.ListTasks(ctx, ownerID, query, page)
+}
+
+// SubtaskTree возвращает задачу со всем деревом потомков.
+func (t *TaskUseCase) SubtaskTree(ctx context.Context, taskID, userID int64) (*entity.TaskTree, error) {
+	root, err := t.access.Task(ctx, taskID, userID, entity.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	descendants, err := t.repo.Descendants(ctx, taskID, root.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.fillProgress(ctx, append([]*entity.Task{root}, descendants...)); err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[int64]*entity.TaskTree, len(descendants)+1)
+	rootNode := &entity.TaskTree{Task: root, Subtasks: []*entity.TaskTree{}}
+	nodes[root.ID] = rootNode
+	for _, d := range descendants {
+		nodes[d.ID] = &entity.TaskTree{Task: d, Subtasks: []*entity.TaskTree{}}
+	}
+	for _, d := range descendants {
+		if d.ParentID == nil {
+			continue
+		}
+		if parent, ok := nodes[*d.ParentID]; ok {
+			parent.Subtasks = append(parent.Subtasks, nodes[d.ID])
+		}
+	}
+	return rootNode, nil
+}
+
+// fillProgress считает для задач процент выполненных прямых подзадач
+// по workflow владельца каждой задачи.
+func (t *TaskUseCase) fillProgress(ctx context.Context, tasks []*entity.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	counts, err := t.repo.ChildStatusCounts(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+	workflows := map[int64]*entity.Workflow{}
+	for _, task := range tasks {
+		byStatus, ok := counts[task.ID]
+		if !ok {
+			continue
+		}
+		wf, ok := workflows[task.OwnerID]
+		if !ok {
+			if wf, err = loadWorkflow(ctx, t.workflow, task.OwnerID); err != nil {
+				return err
+			}
+			workflows[task.OwnerID] = wf
+		}
+		p := &entity.TaskProgress{}
+		for status, n := range byStatus {
+			switch {
+			case wf.IsDone(status):
+				p.Total += n
+				p.Done += n
+			case !wf.IsClosed(status):
+				p.Total += n
+			}
+		}
+		if p.Total > 0 {
+			p.Percent = p.Done * 100 / p.Total
+		}
+		task.Progress = p
+	}
+	return nil
+}
@@ -3,43 +3,436 @@ package usecase
 import (
 	"app/internal/entity"
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 )
 
 type TaskUseCase struct {
-	repo RepoTask
+	repo     RepoTask
+	workflow RepoWorkflow
+	access   *Authorizer
+	subtasks entity.SubtaskPolicies
 }
 
-func NewTaskUseCase(repo RepoTask) *TaskUseCase {
-	return &TaskUseCase{repo: repo}
+func NewTaskUseCase(repo RepoTask, workflow RepoWorkflow, access *Authorizer, subtasks entity.SubtaskPolicies) *TaskUseCase {
+	return &TaskUseCase{repo: repo, workflow: workflow, access: access, subtasks: subtasks}
 }
 
-func (t *TaskUseCase) CreateTask(ctx context.Context, userID int64, title, description string) (*entity.Task, error) {
-	task, err := t.repo.Create(
-		ctx,
-		&entity.Task{
-			OwnerID:     userID,
-			Title:       title,
-			Description: description,
-			Status:      false,
-		})
+// CreateTask создаёт задачу от имени task.OwnerID. Задача в чужом проекте или
+// под чужой задачей достаётся их владельцу: так проект и дерево подзадач
+// всегда принадлежат одному пользователю.
+func (t *TaskUseCase) CreateTask(ctx context.Context, task *entity.Task) (*entity.Task, error) {
+	if err := validateTaskDates(task); err != nil {
+		return nil, err
+	}
+	projectOwner, err := t.projectOwner(ctx, task.OwnerID, task.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	parentOwner, err := t.parentOwner(ctx, task.OwnerID, task.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case projectOwner != 0 && parentOwner != 0 && projectOwner != parentOwner:
+		return nil, fmt.Errorf("%w: проект и родительская задача принадлежат разным владельцам", ErrInvalidInput)
+	case projectOwner != 0:
+		task.OwnerID = projectOwner
+	case parentOwner != 0:
+		task.OwnerID = parentOwner
+	default:
+		if err := denyGuest(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if err := setRecurrence(task, task.Recurrence); err != nil {
+		return nil, err
+	}
+	task.Status = entity.StatusTodo
+	if task.Priority == 0 {
+		task.Priority = entity.PriorityMedium
+	}
+	task, err = t.repo.Create(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// UpdateTask обновляет задачу. Пустой статус означает «не менять»,
+// смена статуса проходит те же проверки, что и TransitionTask.
+func (t *TaskUseCase) UpdateTask(ctx context.Context, userID int64, task *entity.Task) (*entity.Task, error) {
+	if err := validateTaskDates(task); err != nil {
+		return nil, err
+	}
+	current, err := t.access.Task(ctx, task.ID, userID, entity.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	task.OwnerID = current.OwnerID
+	if task.Status == "" {
+		task.Status = current.Status
+	}
+	if task.Priority == 0 {
+		task.Priority = current.Priority
+	}
+	// проект, родитель, исполнитель и повторение меняются только через
+	// MoveTask, SetParent, SetAssignee и SetRecurrence
+	task.ProjectID = current.ProjectID
+	task.ParentID = current.ParentID
+	task.AssigneeID = current.AssigneeID
+	task.Recurrence = current.Recurrence
+	task.RecurrenceStart = current.RecurrenceStart
+	return t.saveWithStatus(ctx, userID, task, current.Status)
+}
+
+// TransitionTask переводит задачу в новое состояние по правилам workflow владельца.
+func (t *TaskUseCase) TransitionTask(ctx context.Context, taskID, userID int64, to entity.TaskStatus) (*entity.Task, error) {
+	task, err := t.statusTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	from := task.Status
+	task.Status = to
+	if from == to {
+		// повторный переход в то же состояние — недопустимый переход
+		return nil, fmt.Errorf("%w: %s → %s", ErrInvalidTransition, from, to)
+	}
+	return t.saveWithStatus(ctx, userID, task, from)
+}
+
+// saveWithStatus сохраняет задачу, у которой статус мог измениться с from на
+// task.Status: проверяет переход и применяет правила для подзадач. Задачу
+// вместе с подзадачами и следующим повторением репозиторий сохраняет в одной
+// транзакции.
+func (t *TaskUseCase) saveWithStatus(ctx context.Context, userID int64, task *entity.Task, from entity.TaskStatus) (*entity.Task, error) {
+	if task.Status == from {
+		return t.repo.Update(ctx, task)
+	}
+	wf, err := t.checkTransition(ctx, task.OwnerID, from, task.Status)
 	if err != nil {
 		return nil, err
 	}
+	completing := wf.IsDone(task.Status) && !wf.IsDone(from)
+	if completing || task.Status == entity.StatusInProgress {
+		// начать или завершить задачу можно только после её блокирующих
+		if err := t.checkBlockers(ctx, task, wf); err != nil {
+			return nil, err
+		}
+	}
+	if completing && t.subtasks.OnComplete == entity.SubtaskBlock {
+		open, err := t.repo.HasDescendantsIn(ctx, task.ID, task.OwnerID, openStatuses(wf))
+		if err != nil {
+			return nil, err
+		}
+		if open {
+			return nil, ErrOpenSubtasks
+		}
+	}
+
+	var effects entity.StatusEffects
+	if completing {
+		effects.Next = nextOccurrence(task)
+		switch t.subtasks.OnComplete {
+		case entity.SubtaskCascade:
+			effects.Cascade, err = t.cascadeComplete(ctx, userID, task, wf)
+			if err != nil {
+				return nil, err
+			}
+		case entity.SubtaskOrphan:
+			effects.DetachChildren = true
+		}
+	}
+	ok, err := t.repo.UpdateStatus(ctx, task, &effects)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrSubtasksChanged
+	}
+	task.NextOccurrence = effects.Next
 	return task, nil
 }
 
-func (t *TaskUseCase) UpdateTask(ctx context.Context, task *entity.Task) (*entity.Task, error) {
+// cascadeComplete готовит закрытие открытых потомков task вместе с ней
+// (SUBTASK_ON_COMPLETE=cascade). Каждый потомок проходит те же проверки,
+// что и при выполнении по отдельности: переход по workflow, блокирующие
+// задачи и роль editor у пользователя — исполнителю родителя чужие
+// подзадачи не достаются. Если хоть одна проверка не прошла, родитель тоже
+// не выполняется.
+func (t *TaskUseCase) cascadeComplete(ctx context.Context, userID int64, task *entity.Task, wf *entity.Workflow) ([]entity.CascadedStatus, error) {
+	descendants, err := t.repo.Descendants(ctx, task.ID, task.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	// блокирующие, которые закрываются в этом же запросе, не мешают
+	closing := map[int64]bool{task.ID: true}
+	var open []*entity.Task
+	for _, d := range descendants {
+		if s, ok := wf.State(d.Status); ok && s.Category == entity.CategoryOpen {
+			open = append(open, d)
+			closing[d.ID] = true
+		}
+	}
+
+	cascade := make([]entity.CascadedStatus, 0, len(open))
+	for _, d := range open {
+		if _, err := t.access.TaskOwner(ctx, d.ID, userID, entity.RoleEditor); err != nil {
+			if errors.Is(err, ErrForbidden) || errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: нужна роль editor в подзадаче %d", ErrForbidden, d.ID)
+			}
+			return nil, err
+		}
+		if !wf.CanTransition(d.Status, entity.StatusDone) {
+			return nil, fmt.Errorf("%w: подзадача %d: %s → %s", ErrInvalidTransition, d.ID, d.Status, entity.StatusDone)
+		}
+		blockers, err := t.repo.BlockersIn(ctx, d.ID, d.OwnerID, openStatuses(wf))
+		if err != nil {
+			return nil, err
+		}
+		var pending []int64
+		for _, id := range blockers {
+			if !closing[id] {
+				pending = append(pending, id)
+			}
+		}
+		if len(pending) > 0 {
+			return nil, fmt.Errorf("%w: подзадача %d: %v", ErrOpenBlockers, d.ID, pending)
+		}
+
+		from := d.Status
+		d.Status = entity.StatusDone
+		cascade = append(cascade, entity.CascadedStatus{Task: d, From: from, Next: nextOccurrence(d)})
+	}
+	return cascade, nil
+}
+
+func openStatuses(wf *entity.Workflow) []entity.TaskStatus {
+	var statuses []entity.TaskStatus
+	for _, s := range wf.States {
+		if s.Category == entity.CategoryOpen {
+			statuses = append(statuses, s.Key)
+		}
+	}
+	return statuses
+}
+
+func (t *TaskUseCase) CompleteTask(ctx context.Context, taskID, userID int64) (*entity.Task, error) {
+	return t.TransitionTask(ctx, taskID, userID, entity.StatusDone)
+}
+
+// MoveTask переносит задачу в другой проект; nil — убрать из проекта.
+// Переносить можно только между проектами владельца задачи.
+func (t *TaskUseCase) MoveTask(ctx context.Context, taskID, userID int64, projectID *int64) (*entity.Task, error) {
+	task, err := t.access.Task(ctx, taskID, userID, entity.RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	projectOwner, err := t.projectOwner(ctx, userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if projectOwner != 0 && projectOwner != task.OwnerID {
+		return nil, fmt.Errorf("%w: проект принадлежит другому владельцу", ErrInvalidInput)
+	}
+	task.ProjectID = projectID
 	return t.repo.Update(ctx, task)
 }
 
-func (t *TaskUseCase) DeleteTask(ctx context.Context, taskID, ownerID int64) error {
+// projectOwner проверяет, что пользователь может добавлять задачи в проект,
+// и возвращает владельца проекта; без проекта — 0.
+func (t *TaskUseCase) projectOwner(ctx context.Context, userID int64, projectID *int64) (int64, error) {
+	if projectID == nil {
+		return 0, nil
+	}
+	project, err := t.access.Project(ctx, *projectID, userID, entity.RoleEditor)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrProjectNotFound
+		}
+		return 0, err
+	}
+	return project.OwnerID, nil
+}
+
+func (t *TaskUseCase) checkTransition(ctx context.Context, ownerID int64, from, to entity.TaskStatus) (*entity.Workflow, error) {
+	wf, err := loadWorkflow(ctx, t.workflow, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := wf.State(to); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownStatus, to)
+	}
+	if !wf.CanTransition(from, to) {
+		return nil, fmt.Errorf("%w: %s → %s", ErrInvalidTransition, from, to)
+	}
+	return wf, nil
+}
+
+// DeleteTask удаляет задачу; что станет с подзадачами, решает SubtaskPolicies.OnDelete.
+func (t *TaskUseCase) DeleteTask(ctx context.Context, taskID, userID int64) error {
+	ownerID, err := t.access.TaskOwner(ctx, taskID, userID, entity.RoleOwner)
+	if err != nil {
+		return err
+	}
+	switch t.subtasks.OnDelete {
+	case entity.SubtaskCascade:
+		return t.repo.DeleteTree(ctx, taskID, ownerID)
+	case entity.SubtaskBlock:
+		has, err := t.repo.HasDescendantsIn(ctx, taskID, ownerID, nil)
+		if err != nil {
+			return err
+		}
+		if has {
+			return ErrHasSubtasks
+		}
+	}
+	// orphan: подзадачи отвяжет ON DELETE SET NULL
 	return t.repo.Delete(ctx, taskID, ownerID)
 }
 
-func (t *TaskUseCase) GetTaskByID(ctx context.Context, taskID, ownerID int64) (*entity.Task, error) {
-	return t.repo.GetByID(ctx, taskID, ownerID)
+func (t *TaskUseCase) GetTaskByID(ctx context.Context, taskID, userID int64) (*entity.Task, error) {
+	task, err := t.access.Task(ctx, taskID, userID, entity.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.fillProgress(ctx, []*entity.Task{task}); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// ListTasks отдаёт страницу задач: своих или, с ScopeShared, доступных
+// пользователю чужих. Курсор — позиция последней выданной задачи в порядке
+// сортировки, поэтому вставки между запросами не сдвигают выдачу.
+func (t *TaskUseCase) ListTasks(ctx context.Context, userID int64, query entity.TaskQuery, page entity.PageRequest) (*entity.TaskPage, error) {
+	switch {
+	case page.Limit < 0:
+		return nil, fmt.Errorf("%w: limit должен быть положительным", ErrInvalidInput)
+	case page.Limit == 0:
+		page.Limit = defaultPageSize
+	case page.Limit > maxPageSize:
+		page.Limit = maxPageSize
+	}
+
+	query.Scope = listScope(query)
+	if query.Overdue {
+		// открытость задачи репозиторий проверяет по workflow её владельца
+		now := time.Now()
+		if query.DueBefore == nil || query.DueBefore.After(now) {
+			query.DueBefore = &now
+		}
+	}
+
+	keys := query.OrderKeys()
+	if page.Cursor != "" {
+		cur, err := decodeCursor(keys, page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query.After = cur
+	}
+
+	// берём на одну задачу больше, чтобы понять, есть ли следующая страница
+	query.Limit = page.Limit + 1
+	tasks, err := t.repo.List(ctx, userID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.TaskPage{Tasks: tasks}
+	if err := t.fillProgress(ctx, tasks); err != nil {
+		return nil, err
+	}
+	if len(tasks) > page.Limit {
+		result.Tasks = tasks[:page.Limit]
+		next, err := encodeCursor(keys, result.Tasks[page.Limit-1])
+		if err != nil {
+			return nil, err
+		}
+		result.NextCursor = next
+	}
+	if page.WithTotal {
+		total, err := t.repo.Count(ctx, userID, query)
+		if err != nil {
+			return nil, err
+		}
+		result.Total = &total
+	}
+	return result, nil
+}
+
+// listScope — фактическая область списка: назначенное мне без явного scope
+// ищем и среди чужих задач.
+func listScope(query entity.TaskQuery) entity.Scope {
+	if query.AssigneeID != nil && query.Scope == "" {
+		return entity.ScopeAll
+	}
+	return query.Scope
+}
+
+// ParseFilter разбирает выражение filter для списка задач пользователя.
+// Статусы сверяются с workflow пользователя, только если в список попадут
+// лишь его задачи: у чужих задач могут быть статусы из workflow их владельцев.
+func (t *TaskUseCase) ParseFilter(ctx context.Context, userID int64, query entity.TaskQuery, src string) (entity.FilterNode, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, nil
+	}
+	var wf *entity.Workflow
+	if scope := listScope(query); scope == "" || scope == entity.ScopeOwn {
+		var err error
+		if wf, err = loadWorkflow(ctx, t.workflow, userID); err != nil {
+			return nil, err
+		}
+	}
+	return parseFilter(src, wf)
+}
+
+var sortFields = map[string]entity.TaskSortField{
+	"id":         entity.SortByID,
+	"title":      entity.SortByTitle,
+	"priority":   entity.SortByPriority,
+	"start_at":   entity.SortByStartAt,
+	"due_at":     entity.SortByDueAt,
+	"created_at": entity.SortByCreatedAt,
+	"updated_at": entity.SortByUpdatedAt,
 }
 
-func (t *TaskUseCase) ListTasks(ctx context.Context, ownerID int64) ([]*entity.Task, error) {
-	return t.repo.List(ctx, ownerID)
+// ParseTaskSort разбирает параметр sort вида "-priority,due_at,created_at":
+// ключи через запятую, минус перед ключом — сортировка по убыванию.
+func ParseTaskSort(raw string) ([]entity.TaskSort, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var sort []entity.TaskSort
+	seen := make(map[entity.TaskSortField]bool)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		desc := strings.HasPrefix(part, "-")
+		field, ok := sortFields[strings.TrimPrefix(part, "-")]
+		if !ok {
+			return nil, fmt.Errorf("%w: нельзя сортировать по %q", ErrInvalidInput, part)
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("%w: ключ сортировки %q указан дважды", ErrInvalidInput, field)
+		}
+		seen[field] = true
+		sort = append(sort, entity.TaskSort{Field: field, Desc: desc})
+	}
+	return sort, nil
+}
+
+func validateTaskDates(task *entity.Task) error {
+	if task.StartAt != nil && task.DueAt != nil && task.DueAt.Before(*task.StartAt) {
+		return fmt.Errorf("%w: due_at раньше start_at", ErrInvalidInput)
+	}
+	return nil
 }
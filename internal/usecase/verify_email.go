@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"app/internal/security"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// verifyEmailTTL — сколько действует ссылка для подтверждения почты.
+	verifyEmailTTL = 48 * time.Hour
+	// verifyEmailInterval — не чаще одного повторного письма за этот срок.
+	verifyEmailInterval = time.Minute
+)
+
+// sendVerification выписывает токен подтверждения и отправляет письмо со
+// ссылкой. Если токен уже выписывался с since, письмо не отправляется.
+func (u *UserUseCase) sendVerification(ctx context.Context, userID int64, email string, since time.Time) error {
+	token, hash, err := security.NewToken()
+	if err != nil {
+		return err
+	}
+	created, err := u.repo.CreateEmailVerification(ctx, userID, hash, time.Now().Add(verifyEmailTTL), since)
+	if err != nil || !created {
+		return err
+	}
+	u.sendAsync(ctx, entity.Email{
+		To:      email,
+		Subject: "Подтверждение почты",
+		Body: "Чтобы подтвердить почту, перейдите по ссылке:\n\n" +
+			u.links.VerifyEmail + token + "\n\n" +
+			"Ссылка действует двое суток. Если вы не регистрировались, просто проигнорируйте это письмо.\n",
+	})
+	return nil
+}
+
+// VerifyEmail подтверждает почту по токену из письма.
+func (u *UserUseCase) VerifyEmail(ctx context.Context, token string) error {
+	if _, err := u.repo.VerifyEmail(ctx, security.HashToken(strings.TrimSpace(token))); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrVerifyTokenInvalid
+		}
+		return err
+	}
+	return nil
+}
+
+// ResendVerification повторно отправляет письмо для подтверждения почты;
+// прежняя ссылка перестаёт действовать.
+func (u *UserUseCase) ResendVerification(ctx context.Context, userID int64) error {
+	user, err := u.repo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+	return u.sendVerification(ctx, user.ID, user.Email, time.Now().Add(-verifyEmailInterval))
+}
+
+// CheckCanCreateTasks отказывает пользователю с неподтверждённой почтой,
+// если сервис так настроен.
+func (u *UserUseCase) CheckCanCreateTasks(user *entity.User) error {
+	if u.requireVerifiedEmail && user.EmailVerifiedAt == nil {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
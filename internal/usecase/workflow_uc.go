@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var stateKeyRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+type WorkflowUseCase struct {
+	repo RepoWorkflow
+}
+
+func NewWorkflowUseCase(repo RepoWorkflow) *WorkflowUseCase {
+	return &WorkflowUseCase{repo: repo}
+}
+
+// loadWorkflow собирает действующий workflow пользователя.
+func loadWorkflow(ctx context.Context, repo RepoWorkflow, ownerID int64) (*entity.Workflow, error) {
+	states, err := repo.ListStates(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	transitions, err := repo.ListTransitions(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return entity.NewWorkflow(states, transitions), nil
+}
+
+func (w *WorkflowUseCase) GetWorkflow(ctx context.Context, ownerID int64) (*entity.Workflow, error) {
+	return loadWorkflow(ctx, w.repo, ownerID)
+}
+
+func (w *WorkflowUseCase) CreateState(ctx context.Context, ownerID int64, key entity.TaskStatus, name string, category entity.StatusCategory) (*entity.WorkflowState, error) {
+	if !stateKeyRe.MatchString(string(key)) {
+		return nil, fmt.Errorf("%w: ключ статуса должен состоять из a-z, 0-9 и _", ErrInvalidInput)
+	}
+	if key == "open" || key == "closed" {
+		// зарезервированы языком фильтров: status:open, status:closed
+		return nil, fmt.Errorf("%w: ключ %q зарезервирован", ErrInvalidInput, key)
+	}
+	if !category.Valid() {
+		return nil, fmt.Errorf("%w: категория должна быть open, done или cancelled", ErrInvalidInput)
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = string(key)
+	}
+
+	wf, err := loadWorkflow(ctx, w.repo, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := wf.State(key); ok {
+		return nil, ErrStateExists
+	}
+
+	state := entity.WorkflowState{Key: key, Name: name, Category: category, Custom: true}
+	if err := w.repo.CreateState(ctx, ownerID, state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (w *WorkflowUseCase) DeleteState(ctx context.Context, ownerID int64, key entity.TaskStatus) error {
+	if entity.IsBuiltinStatus(key) {
+		return fmt.Errorf("%w: встроенный статус нельзя удалить", ErrInvalidInput)
+	}
+	inUse, err := w.repo.StateInUse(ctx, ownerID, key)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrStateInUse
+	}
+	return w.repo.DeleteState(ctx, ownerID, key)
+}
+
+func (w *WorkflowUseCase) AddTransition(ctx context.Context, ownerID int64, from, to entity.TaskStatus) error {
+	wf, err := loadWorkflow(ctx, w.repo, ownerID)
+	if err != nil {
+		return err
+	}
+	if _, ok := wf.State(from); !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownStatus, from)
+	}
+	if _, ok := wf.State(to); !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownStatus, to)
+	}
+	if from == to {
+		return fmt.Errorf("%w: переход в то же состояние", ErrInvalidInput)
+	}
+	if wf.CanTransition(from, to) {
+		return nil
+	}
+	return w.repo.AddTransition(ctx, ownerID, from, to)
+}
+
+func (w *WorkflowUseCase) DeleteTransition(ctx context.Context, ownerID int64, from, to entity.TaskStatus) error {
+	return w.repo.DeleteTransition(ctx, ownerID, from, to)
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"app/internal/entity"
+	"app/internal/security"
+	"app/internal/tenant"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// invitationTTL — сколько действует приглашение в пространство.
+const invitationTTL = 7 * 24 * time.Hour
+
+// WorkspaceUseCase управляет рабочими пространствами, участниками и
+// приглашениями, а также определяет пространство запроса.
+type WorkspaceUseCase struct {
+	repo  RepoWorkspace
+	users RepoUser
+}
+
+func NewWorkspaceUseCase(repo RepoWorkspace, users RepoUser) *WorkspaceUseCase {
+	return &WorkspaceUseCase{repo: repo, users: users}
+}
+
+// Resolve возвращает членство userID в пространстве workspaceID, а без него —
+// в личном пространстве пользователя. Если пользователь не участник,
+// возвращается ErrForbidden.
+func (u *WorkspaceUseCase) Resolve(ctx context.Context, userID int64, workspaceID *int64) (*entity.Membership, error) {
+	var (
+		m   *entity.Membership
+		err error
+	)
+	if workspaceID == nil {
+		m, err = u.repo.Personal(ctx, userID)
+	} else {
+		m, err = u.repo.Membership(ctx, *workspaceID, userID)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: вы не участник этого рабочего пространства", ErrForbidden)
+	}
+	return m, err
+}
+
+func (u *WorkspaceUseCase) ListWorkspaces(ctx context.Context, userID int64) ([]*entity.Workspace, error) {
+	return u.repo.ListForUser(ctx, userID)
+}
+
+// CreateWorkspace заводит пространство; создатель становится администратором.
+func (u *WorkspaceUseCase) CreateWorkspace(ctx context.Context, userID int64, name string) (*entity.Workspace, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: у пространства должно быть имя", ErrInvalidInput)
+	}
+	return u.repo.Create(ctx, name, userID)
+}
+
+// member возвращает членство userID в пространстве. Для чужого пространства —
+// sql.ErrNoRows, как и для несуществующего; роль ниже min — ErrForbidden.
+func (u *WorkspaceUseCase) member(ctx context.Context, workspaceID, userID int64, min entity.WorkspaceRole) (*entity.Membership, error) {
+	m, err := u.repo.Membership(ctx, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if min == entity.WorkspaceAdmin && m.Role != entity.WorkspaceAdmin {
+		return nil, fmt.Errorf("%w: нужна роль admin, у вас %s", ErrForbidden, m.Role)
+	}
+	return m, nil
+}
+
+// ListMembers отдаёт участников пространства; видно любому участнику.
+func (u *WorkspaceUseCase) ListMembers(ctx context.Context, workspaceID, userID int64) ([]*entity.Membership, error) {
+	if _, err := u.member(ctx, workspaceID, userID, entity.WorkspaceGuest); err != nil {
+		return nil, err
+	}
+	return u.repo.Members(ctx, workspaceID)
+}
+
+// SetMemberRole меняет роль участника; доступно администраторам.
+func (u *WorkspaceUseCase) SetMemberRole(ctx context.Context, workspaceID, userID, memberID int64, role entity.WorkspaceRole) (*entity.Membership, error) {
+	if !role.Valid() {
+		return nil, fmt.Errorf("%w: роль должна быть admin, member или guest", ErrInvalidInput)
+	}
+	if _, err := u.member(ctx, workspaceID, userID, entity.WorkspaceAdmin); err != nil {
+		return nil, err
+	}
+	ok, err := u.repo.SetRole(ctx, workspaceID, memberID, role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMemberNotFound
+		}
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrLastAdmin
+	}
+	return u.repo.Membership(ctx, workspaceID, memberID)
+}
+
+// RemoveMember исключает участника. Чужое членство снимает администратор,
+// из пространства участник может выйти сам.
+func (u *WorkspaceUseCase) RemoveMember(ctx context.Context, workspaceID, userID, memberID int64) error {
+	min := entity.WorkspaceAdmin
+	if memberID == userID {
+		min = entity.WorkspaceGuest
+	}
+	if _, err := u.member(ctx, workspaceID, userID, min); err != nil {
+		return err
+	}
+	ok, err := u.repo.RemoveMember(ctx, workspaceID, memberID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrMemberNotFound
+		}
+		return err
+	}
+	if !ok {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
+// Invite выписывает приглашение на почту email. Токен есть только в ответе:
+// в базе хранится его хэш.
+func (u *WorkspaceUseCase) Invite(ctx context.Context, workspaceID, userID int64, email string, role entity.WorkspaceRole) (*entity.Invitation, error) {
+	if role == "" {
+		role = entity.WorkspaceMember
+	}
+	if !role.Valid() {
+		return nil, fmt.Errorf("%w: роль должна быть admin, member или guest", ErrInvalidInput)
+	}
+	email = normalizeEmail(email)
+	if email == "" {
+		return nil, fmt.Errorf("%w: нужна почта приглашённого", ErrInvalidInput)
+	}
+	if _, err := u.member(ctx, workspaceID, userID, entity.WorkspaceAdmin); err != nil {
+		return nil, err
+	}
+	token, hash, err := security.NewToken()
+	if err != nil {
+		return nil, err
+	}
+	inv, err := u.repo.CreateInvitation(ctx, &entity.Invitation{
+		WorkspaceID: workspaceID,
+		Email:       email,
+		Role:        role,
+		ExpiresAt:   time.Now().Add(invitationTTL),
+	}, hash, userID)
+	if err != nil {
+		return nil, err
+	}
+	inv.Token = token
+	return inv, nil
+}
+
+// AcceptInvitation добавляет пользователя в пространство по токену
+// приглашения. Приглашение действует один раз и только для той почты,
+// на которую выписано.
+func (u *WorkspaceUseCase) AcceptInvitation(ctx context.Context, userID int64, token string) (*entity.Membership, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, fmt.Errorf("%w: нужен токен приглашения", ErrInvalidInput)
+	}
+	user, err := u.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	m, err := u.repo.AcceptInvitation(ctx, security.HashToken(token), userID, user.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+// denyGuest запрещает гостю пространства заводить собственные задачи и
+// проекты: он работает только с тем, к чему ему выдали доступ.
+func denyGuest(ctx context.Context) error {
+	if m, ok := tenant.From(ctx); ok && m.Role == entity.WorkspaceGuest {
+		return fmt.Errorf("%w: гость пространства работает только с выданными ему задачами и проектами", ErrForbidden)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+UPDATE tasks t
+SET status = 'done'
+FROM workflow_states ws
+WHERE ws.owner_id = t.owner_id AND ws.key = t.status AND ws.category = 'done';
+
+DROP TABLE IF EXISTS workflow_transitions;
+DROP TABLE IF EXISTS workflow_states;
+
+ALTER TABLE tasks ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE tasks
+    ALTER COLUMN status TYPE BOOLEAN
+        USING status = 'done';
+ALTER TABLE tasks ALTER COLUMN status SET DEFAULT false;
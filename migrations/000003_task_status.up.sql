@@ -0,0 +1,21 @@
+ALTER TABLE tasks ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE tasks
+    ALTER COLUMN status TYPE TEXT
+        USING CASE WHEN status THEN 'done' ELSE 'todo' END;
+ALTER TABLE tasks ALTER COLUMN status SET DEFAULT 'todo';
+
+CREATE TABLE workflow_states (
+                                 owner_id   BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                 key        TEXT NOT NULL,
+                                 name       TEXT NOT NULL,
+                                 category   TEXT NOT NULL CHECK (category IN ('open', 'done', 'cancelled')),
+                                 created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                 PRIMARY KEY (owner_id, key)
+);
+
+CREATE TABLE workflow_transitions (
+                                      owner_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                      from_status TEXT NOT NULL,
+                                      to_status   TEXT NOT NULL,
+                                      PRIMARY KEY (owner_id, from_status, to_status)
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS tasks_owner_due_at_idx;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS due_at,
+    DROP COLUMN IF EXISTS start_at;
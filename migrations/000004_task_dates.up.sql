@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+    ADD COLUMN start_at TIMESTAMPTZ,
+    ADD COLUMN due_at   TIMESTAMPTZ;
+
+CREATE INDEX tasks_owner_due_at_idx ON tasks (owner_id, due_at) WHERE due_at IS NOT NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE tasks
+    ADD COLUMN priority SMALLINT NOT NULL DEFAULT 2 CHECK (priority BETWEEN 1 AND 4);
+
+CREATE INDEX tasks_owner_priority_idx ON tasks (owner_id, priority DESC, id DESC);
@@ -0,0 +1,9 @@
+-- Конфигурация russian стеммит кириллицу русским стеммером, а латиницу —
+-- английским, поэтому одной конфигурации хватает на оба языка.
+ALTER TABLE tasks
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX tasks_search_vector_idx ON tasks USING GIN (search_vector);
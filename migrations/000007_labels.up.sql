@@ -0,0 +1,18 @@
+CREATE TABLE labels (
+                        id         BIGSERIAL PRIMARY KEY,
+                        owner_id   BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                        name       TEXT NOT NULL,
+                        color      TEXT NOT NULL DEFAULT '#808080',
+                        created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                        updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX labels_owner_name_idx ON labels (owner_id, lower(name));
+
+CREATE TABLE task_labels (
+                             task_id  BIGINT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+                             label_id BIGINT NOT NULL REFERENCES labels(id) ON DELETE CASCADE,
+                             PRIMARY KEY (task_id, label_id)
+);
+
+CREATE INDEX task_labels_label_id_idx ON task_labels (label_id);
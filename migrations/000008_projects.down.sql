@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS tasks_project_id_idx;
+ALTER TABLE tasks DROP COLUMN IF EXISTS project_id;
+
+DROP TABLE IF EXISTS projects;
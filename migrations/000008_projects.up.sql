@@ -0,0 +1,16 @@
+CREATE TABLE projects (
+                          id          BIGSERIAL PRIMARY KEY,
+                          owner_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                          name        TEXT NOT NULL,
+                          description TEXT NOT NULL DEFAULT '',
+                          created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+                          updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX projects_owner_id_idx ON projects (owner_id);
+
+-- при удалении проекта задачи остаются у владельца без проекта
+ALTER TABLE tasks
+    ADD COLUMN project_id BIGINT REFERENCES projects(id) ON DELETE SET NULL;
+
+CREATE INDEX tasks_project_id_idx ON tasks (project_id) WHERE project_id IS NOT NULL;
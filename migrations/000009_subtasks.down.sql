@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS tasks_parent_id_idx;
+
+ALTER TABLE tasks
+    DROP CONSTRAINT IF EXISTS tasks_parent_not_self,
+    DROP COLUMN IF EXISTS parent_id;
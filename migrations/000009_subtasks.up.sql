@@ -0,0 +1,7 @@
+-- Что делать с подзадачами при удалении родителя, решает приложение
+-- (SUBTASK_ON_DELETE); SET NULL здесь — страховка для режима orphan.
+ALTER TABLE tasks
+    ADD COLUMN parent_id BIGINT REFERENCES tasks(id) ON DELETE SET NULL,
+    ADD CONSTRAINT tasks_parent_not_self CHECK (parent_id <> id);
+
+CREATE INDEX tasks_parent_id_idx ON tasks (parent_id) WHERE parent_id IS NOT NULL;
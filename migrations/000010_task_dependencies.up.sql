@@ -0,0 +1,10 @@
+-- task_id нельзя начать или завершить, пока не выполнена depends_on_id
+CREATE TABLE task_dependencies (
+                                   task_id       BIGINT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+                                   depends_on_id BIGINT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+                                   created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                   PRIMARY KEY (task_id, depends_on_id),
+                                   CHECK (task_id <> depends_on_id)
+);
+
+CREATE INDEX task_dependencies_depends_on_id_idx ON task_dependencies (depends_on_id);
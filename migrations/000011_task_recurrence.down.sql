@@ -0,0 +1,3 @@
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS recurrence_start,
+    DROP COLUMN IF EXISTS recurrence;
@@ -0,0 +1,5 @@
+-- recurrence — правило RRULE в каноническом виде, '' — задача не повторяется;
+-- recurrence_start — DTSTART серии, от него отсчитываются повторения и COUNT
+ALTER TABLE tasks
+    ADD COLUMN recurrence TEXT NOT NULL DEFAULT '',
+    ADD COLUMN recurrence_start TIMESTAMPTZ;
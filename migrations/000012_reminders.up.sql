@@ -0,0 +1,18 @@
+-- Напоминание задаётся либо смещением от due_at, либо абсолютным временем.
+-- locked_until — аренда строки планировщиком (и время следующей попытки после
+-- ошибки): пока она не истекла, другие реплики напоминание не берут.
+CREATE TABLE reminders (
+                           id             BIGSERIAL PRIMARY KEY,
+                           task_id        BIGINT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+                           offset_minutes INT CHECK (offset_minutes >= 0),
+                           remind_at      TIMESTAMPTZ,
+                           sent_at        TIMESTAMPTZ,
+                           attempts       INT NOT NULL DEFAULT 0,
+                           last_error     TEXT NOT NULL DEFAULT '',
+                           locked_until   TIMESTAMPTZ,
+                           created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+                           CHECK ((offset_minutes IS NULL) <> (remind_at IS NULL))
+);
+
+CREATE INDEX reminders_task_id_idx ON reminders (task_id);
+CREATE INDEX reminders_pending_idx ON reminders (id) WHERE sent_at IS NULL;
@@ -0,0 +1,10 @@
+CREATE TABLE comments (
+                          id         BIGSERIAL PRIMARY KEY,
+                          task_id    BIGINT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+                          author_id  BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                          body       TEXT NOT NULL,
+                          created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                          edited_at  TIMESTAMPTZ
+);
+
+CREATE INDEX comments_task_id_idx ON comments (task_id, id);
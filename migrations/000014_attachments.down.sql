@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS attachments_queue_blob_deletion ON attachments;
+DROP FUNCTION IF EXISTS queue_blob_deletion();
+DROP TABLE IF EXISTS blob_deletions;
+DROP TABLE IF EXISTS attachments;
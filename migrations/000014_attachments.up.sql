@@ -0,0 +1,31 @@
+CREATE TABLE attachments (
+                             id           BIGSERIAL PRIMARY KEY,
+                             task_id      BIGINT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+                             uploader_id  BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                             filename     TEXT NOT NULL,
+                             content_type TEXT NOT NULL,
+                             size         BIGINT NOT NULL,
+                             storage_key  TEXT NOT NULL UNIQUE,
+                             created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX attachments_task_id_idx ON attachments (task_id);
+
+-- Файлы в хранилище удаляет фоновая задача. Триггер ставит ключ в очередь при
+-- любом удалении строки, в том числе каскадном вместе с задачей или
+-- пользователем, и в той же транзакции — ключ не потеряется.
+CREATE TABLE blob_deletions (
+                                storage_key TEXT PRIMARY KEY,
+                                created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE FUNCTION queue_blob_deletion() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO blob_deletions (storage_key) VALUES (OLD.storage_key) ON CONFLICT DO NOTHING;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER attachments_queue_blob_deletion
+    AFTER DELETE ON attachments
+    FOR EACH ROW EXECUTE FUNCTION queue_blob_deletion();
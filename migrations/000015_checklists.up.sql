@@ -0,0 +1,12 @@
+-- Позиции плотные (0..n-1). Уникальность проверяется в конце транзакции,
+-- чтобы сдвиг пунктов одним UPDATE не упирался в промежуточные дубли.
+CREATE TABLE checklist_items (
+                                 id         BIGSERIAL PRIMARY KEY,
+                                 task_id    BIGINT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+                                 text       TEXT NOT NULL,
+                                 done       BOOLEAN NOT NULL DEFAULT false,
+                                 position   INT NOT NULL CHECK (position >= 0),
+                                 created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                 updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                 CONSTRAINT checklist_items_position_key UNIQUE (task_id, position) DEFERRABLE INITIALLY DEFERRED
+);
@@ -0,0 +1,21 @@
+-- доступ к чужим задачам и проектам; владелец задаётся owner_id и здесь не хранится
+CREATE TABLE task_shares (
+                             task_id    BIGINT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+                             user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                             role       TEXT NOT NULL CHECK (role IN ('viewer', 'editor', 'owner')),
+                             created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                             PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX task_shares_user_id_idx ON task_shares (user_id);
+
+-- доступ к проекту распространяется на все его задачи
+CREATE TABLE project_shares (
+                                project_id BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+                                user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                role       TEXT NOT NULL CHECK (role IN ('viewer', 'editor', 'owner')),
+                                created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                PRIMARY KEY (project_id, user_id)
+);
+
+CREATE INDEX project_shares_user_id_idx ON project_shares (user_id);
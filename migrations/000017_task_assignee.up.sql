@@ -0,0 +1,5 @@
+-- исполнитель задачи; владелец (owner_id) — тот, кто её завёл
+ALTER TABLE tasks
+    ADD COLUMN assignee_id BIGINT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX tasks_assignee_id_idx ON tasks (assignee_id) WHERE assignee_id IS NOT NULL;
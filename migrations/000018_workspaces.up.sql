@@ -0,0 +1,64 @@
+-- рабочие пространства (команды); у каждого пользователя есть личное
+CREATE TABLE workspaces (
+                            id                BIGSERIAL PRIMARY KEY,
+                            name              TEXT NOT NULL,
+                            personal_owner_id BIGINT UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+                            created_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE workspace_members (
+                                   workspace_id BIGINT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+                                   user_id      BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                   role         TEXT NOT NULL CHECK (role IN ('admin', 'member', 'guest')),
+                                   created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+                                   PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX workspace_members_user_id_idx ON workspace_members (user_id);
+
+-- в базе хранится только хэш токена приглашения
+CREATE TABLE workspace_invitations (
+                                       id           BIGSERIAL PRIMARY KEY,
+                                       workspace_id BIGINT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+                                       email        TEXT NOT NULL,
+                                       role         TEXT NOT NULL CHECK (role IN ('admin', 'member', 'guest')),
+                                       token_hash   TEXT NOT NULL UNIQUE,
+                                       invited_by   BIGINT REFERENCES users(id) ON DELETE SET NULL,
+                                       expires_at   TIMESTAMPTZ NOT NULL,
+                                       accepted_at  TIMESTAMPTZ,
+                                       created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX workspace_invitations_workspace_id_idx ON workspace_invitations (workspace_id);
+
+-- существующие пользователи получают личные пространства, куда переезжают их данные
+INSERT INTO workspaces (name, personal_owner_id)
+SELECT 'Личное', id FROM users;
+
+INSERT INTO workspace_members (workspace_id, user_id, role)
+SELECT id, personal_owner_id, 'admin' FROM workspaces;
+
+ALTER TABLE projects ADD COLUMN workspace_id BIGINT REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE tasks ADD COLUMN workspace_id BIGINT REFERENCES workspaces(id) ON DELETE CASCADE;
+
+UPDATE projects p SET workspace_id = w.id FROM workspaces w WHERE w.personal_owner_id = p.owner_id;
+UPDATE tasks t SET workspace_id = w.id FROM workspaces w WHERE w.personal_owner_id = t.owner_id;
+
+ALTER TABLE projects ALTER COLUMN workspace_id SET NOT NULL;
+ALTER TABLE tasks ALTER COLUMN workspace_id SET NOT NULL;
+
+-- тем, кому уже выдан доступ, нужно членство в пространстве владельца
+INSERT INTO workspace_members (workspace_id, user_id, role)
+SELECT w.id, s.user_id, 'guest'
+FROM task_shares s
+JOIN tasks t ON t.id = s.task_id
+JOIN workspaces w ON w.id = t.workspace_id
+UNION
+SELECT w.id, s.user_id, 'guest'
+FROM project_shares s
+JOIN projects p ON p.id = s.project_id
+JOIN workspaces w ON w.id = p.workspace_id
+ON CONFLICT DO NOTHING;
+
+CREATE INDEX projects_workspace_owner_idx ON projects (workspace_id, owner_id);
+CREATE INDEX tasks_workspace_owner_idx ON tasks (workspace_id, owner_id);
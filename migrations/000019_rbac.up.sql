@@ -0,0 +1,61 @@
+-- системные роли пользователей и их разрешения (RBAC); не путать с ролями
+-- доступа к задачам (task_shares) и ролями в рабочих пространствах
+CREATE TABLE roles (
+                       name        TEXT PRIMARY KEY,
+                       description TEXT NOT NULL DEFAULT '',
+                       created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE permissions (
+                             name        TEXT PRIMARY KEY,
+                             description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE role_permissions (
+                                  role       TEXT NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+                                  permission TEXT NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+                                  PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE user_roles (
+                            user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                            role       TEXT NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+                            granted_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+                            created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                            PRIMARY KEY (user_id, role)
+);
+
+CREATE INDEX user_roles_role_idx ON user_roles (role);
+
+INSERT INTO permissions (name, description) VALUES
+    ('tasks:read', 'читать задачи'),
+    ('tasks:write', 'создавать и изменять задачи'),
+    ('tasks:delete', 'удалять задачи'),
+    ('projects:read', 'читать проекты'),
+    ('projects:write', 'создавать и изменять проекты'),
+    ('projects:delete', 'удалять проекты'),
+    ('labels:read', 'читать метки'),
+    ('labels:write', 'управлять метками'),
+    ('workflow:read', 'читать workflow'),
+    ('workflow:write', 'настраивать workflow'),
+    ('workspaces:read', 'видеть рабочие пространства и их участников'),
+    ('workspaces:write', 'создавать пространства, приглашать и управлять участниками'),
+    ('roles:manage', 'выдавать и отзывать системные роли');
+
+INSERT INTO roles (name, description) VALUES
+    ('admin', 'все разрешения, включая управление ролями'),
+    ('user', 'обычный пользователь'),
+    ('reader', 'только чтение');
+
+INSERT INTO role_permissions (role, permission)
+SELECT 'admin', name FROM permissions;
+
+INSERT INTO role_permissions (role, permission)
+SELECT 'user', name FROM permissions WHERE name <> 'roles:manage';
+
+INSERT INTO role_permissions (role, permission)
+SELECT 'reader', name FROM permissions WHERE name LIKE '%:read';
+
+-- существующие пользователи получают роль по умолчанию
+INSERT INTO user_roles (user_id, role)
+SELECT id, 'user' FROM users;
@@ -0,0 +1,5 @@
+DELETE FROM permissions WHERE name = 'users:manage';
+DROP TABLE IF EXISTS password_resets;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS must_reset_password,
+    DROP COLUMN IF EXISTS disabled_at;
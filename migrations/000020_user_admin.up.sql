@@ -0,0 +1,21 @@
+-- отключённые аккаунты не могут войти, их токены отклоняются
+ALTER TABLE users
+    ADD COLUMN disabled_at         TIMESTAMPTZ,
+    ADD COLUMN must_reset_password BOOLEAN NOT NULL DEFAULT false;
+
+-- одноразовые токены сброса пароля; хранится только хэш
+CREATE TABLE password_resets (
+                                 id         BIGSERIAL PRIMARY KEY,
+                                 user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                 token_hash TEXT NOT NULL UNIQUE,
+                                 expires_at TIMESTAMPTZ NOT NULL,
+                                 used_at    TIMESTAMPTZ,
+                                 created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX password_resets_user_id_idx ON password_resets (user_id);
+
+INSERT INTO permissions (name, description) VALUES
+    ('users:manage', 'просматривать, отключать и удалять пользователей');
+
+INSERT INTO role_permissions (role, permission) VALUES ('admin', 'users:manage');
@@ -0,0 +1,16 @@
+-- refresh-токены; хранится только хэш. Токены одного входа образуют
+-- семейство (family_id = id первого токена): при обновлении используемый
+-- токен помечается used_at и заменяется новым, а повторное предъявление
+-- использованного отзывает всё семейство.
+CREATE TABLE refresh_tokens (
+                                id         BIGSERIAL PRIMARY KEY,
+                                user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                family_id  BIGINT NOT NULL,
+                                token_hash TEXT NOT NULL UNIQUE,
+                                expires_at TIMESTAMPTZ NOT NULL,
+                                used_at    TIMESTAMPTZ,
+                                revoked_at TIMESTAMPTZ,
+                                created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX refresh_tokens_family_id_idx ON refresh_tokens (family_id);
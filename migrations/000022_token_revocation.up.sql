@@ -0,0 +1,13 @@
+-- JWT, выданные раньше этого момента, больше не принимаются
+-- (выход со всех устройств, смена пароля)
+ALTER TABLE users ADD COLUMN tokens_valid_after TIMESTAMPTZ;
+
+-- отозванные до истечения JWT (выход); хранятся, пока токен не истечёт сам
+CREATE TABLE revoked_tokens (
+                                jti        TEXT PRIMARY KEY,
+                                user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                expires_at TIMESTAMPTZ NOT NULL,
+                                revoked_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX revoked_tokens_revoked_at_idx ON revoked_tokens (revoked_at);
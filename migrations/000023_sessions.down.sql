@@ -0,0 +1,4 @@
+ALTER TABLE refresh_tokens DROP CONSTRAINT IF EXISTS refresh_tokens_session_id_fkey;
+ALTER INDEX IF EXISTS refresh_tokens_session_id_idx RENAME TO refresh_tokens_family_id_idx;
+ALTER TABLE refresh_tokens RENAME COLUMN session_id TO family_id;
+DROP TABLE IF EXISTS sessions;
@@ -0,0 +1,29 @@
+-- сессия — один вход пользователя (устройство). Её refresh-токены — бывшее
+-- семейство refresh_tokens.family_id, а JWT несут её id в claim sid.
+CREATE TABLE sessions (
+                          id           BIGSERIAL PRIMARY KEY,
+                          user_id      BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                          user_agent   TEXT NOT NULL DEFAULT '',
+                          ip           TEXT NOT NULL DEFAULT '',
+                          created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+                          last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+                          revoked_at   TIMESTAMPTZ
+);
+
+CREATE INDEX sessions_user_id_idx ON sessions (user_id);
+CREATE INDEX sessions_revoked_at_idx ON sessions (revoked_at) WHERE revoked_at IS NOT NULL;
+
+-- существующие семейства становятся сессиями с тем же id
+INSERT INTO sessions (id, user_id, created_at, last_seen_at, revoked_at)
+SELECT family_id, min(user_id), min(created_at), max(created_at),
+       CASE WHEN count(*) FILTER (WHERE revoked_at IS NULL) = 0 THEN max(revoked_at) END
+FROM refresh_tokens
+GROUP BY family_id;
+
+SELECT setval(pg_get_serial_sequence('sessions', 'id'), COALESCE((SELECT max(id) FROM sessions), 0) + 1, false);
+
+ALTER TABLE refresh_tokens RENAME COLUMN family_id TO session_id;
+ALTER INDEX refresh_tokens_family_id_idx RENAME TO refresh_tokens_session_id_idx;
+ALTER TABLE refresh_tokens
+    ADD CONSTRAINT refresh_tokens_session_id_fkey
+        FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE;
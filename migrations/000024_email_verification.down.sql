@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS users_email_lower_key;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+DROP TABLE IF EXISTS email_verifications;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
@@ -0,0 +1,28 @@
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMPTZ;
+
+-- аккаунты, заведённые до появления подтверждения, считаются подтверждёнными
+UPDATE users SET email_verified_at = created_at;
+
+-- одноразовые токены подтверждения почты; хранится только хэш
+CREATE TABLE email_verifications (
+                                     id         BIGSERIAL PRIMARY KEY,
+                                     user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                     token_hash TEXT NOT NULL UNIQUE,
+                                     expires_at TIMESTAMPTZ NOT NULL,
+                                     used_at    TIMESTAMPTZ,
+                                     created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX email_verifications_user_id_idx ON email_verifications (user_id);
+
+-- почта уникальна без учёта регистра: User@Mail.com и user@mail.com — один
+-- аккаунт. Если такие дубли уже есть, их нужно развести вручную до миграции.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM users GROUP BY lower(email) HAVING count(*) > 1) THEN
+        RAISE EXCEPTION 'users: emails differing only by case must be resolved before this migration';
+    END IF;
+END $$;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX users_email_lower_key ON users (lower(email));
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS labels_workspace_name_idx;
+-- при откате у автора могут оказаться одноимённые метки из разных пространств
+DELETE FROM labels l
+USING labels d
+WHERE d.owner_id = l.owner_id AND lower(d.name) = lower(l.name) AND d.id < l.id;
+CREATE UNIQUE INDEX labels_owner_name_idx ON labels (owner_id, lower(name));
+ALTER TABLE labels DROP COLUMN IF EXISTS workspace_id;
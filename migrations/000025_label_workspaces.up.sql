@@ -0,0 +1,35 @@
+-- метки принадлежат рабочему пространству, а не пользователю; owner_id
+-- остаётся автором метки
+ALTER TABLE labels ADD COLUMN workspace_id BIGINT REFERENCES workspaces(id) ON DELETE CASCADE;
+
+UPDATE labels l SET workspace_id = w.id FROM workspaces w WHERE w.personal_owner_id = l.owner_id;
+
+DROP INDEX IF EXISTS labels_owner_name_idx;
+CREATE UNIQUE INDEX labels_workspace_name_idx ON labels (workspace_id, lower(name));
+
+-- метки, висящие на задачах других пространств, копируются туда
+-- (одноимённые метки разных авторов сливаются в одну)
+INSERT INTO labels (owner_id, workspace_id, name, color, created_at, updated_at)
+SELECT DISTINCT ON (t.workspace_id, lower(l.name))
+       l.owner_id, t.workspace_id, l.name, l.color, l.created_at, l.updated_at
+FROM task_labels tl
+JOIN labels l ON l.id = tl.label_id
+JOIN tasks t ON t.id = tl.task_id
+WHERE t.workspace_id <> l.workspace_id
+ORDER BY t.workspace_id, lower(l.name), l.id
+ON CONFLICT (workspace_id, lower(name)) DO NOTHING;
+
+INSERT INTO task_labels (task_id, label_id)
+SELECT tl.task_id, c.id
+FROM task_labels tl
+JOIN labels l ON l.id = tl.label_id
+JOIN tasks t ON t.id = tl.task_id
+JOIN labels c ON c.workspace_id = t.workspace_id AND lower(c.name) = lower(l.name)
+WHERE t.workspace_id <> l.workspace_id
+ON CONFLICT DO NOTHING;
+
+DELETE FROM task_labels tl
+USING labels l, tasks t
+WHERE l.id = tl.label_id AND t.id = tl.task_id AND t.workspace_id <> l.workspace_id;
+
+ALTER TABLE labels ALTER COLUMN workspace_id SET NOT NULL;